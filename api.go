@@ -0,0 +1,167 @@
+package main
+
+import (
+	"strings"
+)
+
+// apiRoot holds the parts of the API root used to detect which API it belongs to, its shape differs between Rancher releases
+type apiRoot struct {
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	ResourceType string `json:"resourceType"`
+	APIVersion   struct {
+		Version string `json:"version"`
+	} `json:"apiVersion"`
+	Links map[string]string `json:"links"`
+	// Data lists the schemas of the Steve API, each linking to its collection
+	Data []struct {
+		ID    string            `json:"id"`
+		Links map[string]string `json:"links"`
+	} `json:"data"`
+}
+
+// version - Reads the API version from the root, Rancher 1.x roots are an `apiVersion` while the v3 root names its version and Steve lists its schemas
+func (r *apiRoot) version() string {
+	switch {
+	case r.APIVersion.Version != "":
+		return r.APIVersion.Version
+	case r.Type == "apiVersion":
+		return r.ID
+	case r.ResourceType == "schema":
+		return apiSteve
+	}
+	return ""
+}
+
+// collections - Maps the lower cased name of each collection linked from the root to its URL, Steve collections are named by schema
+func (r *apiRoot) collections() map[string]string {
+	collections := make(map[string]string)
+	for name, link := range r.Links {
+		collections[strings.ToLower(name)] = link
+	}
+	for _, schema := range r.Data {
+		if link, ok := schema.Links["collection"]; ok {
+			collections[schema.ID] = link
+		}
+	}
+	return collections
+}
+
+// detectAPI - Probes the API root behind CATTLE_URL, choosing the endpoints to trawl from the collections it links to.
+// The version is left empty if the root could not be read, in which case it is probed again on the next scrape.
+// Collectors keep their last known state until the root is read successfully.
+func (e *Exporter) detectAPI() {
+	e.apiVersion = ""
+	e.endpoints = nil
+	e.collections = nil
+
+	var root apiRoot
+	if err := getJSON(strings.TrimSuffix(e.rancherURL, "/"), e.accessKey, e.secretKey, &root); err != nil {
+		log.Error("Failed to read the Rancher API root: ", err)
+		return
+	}
+
+	version := root.version()
+
+	var candidates []string
+	switch {
+	case version == apiSteve:
+		candidates = endpointsSteve
+	case version == "v3":
+		candidates = endpointsV3
+	case version == "v1" || version == "v2-beta":
+		candidates = endpoints
+	default:
+		log.Errorf("Unsupported Rancher API at %s, type %q version %q", e.rancherURL, root.Type, version)
+		return
+	}
+
+	collections := root.collections()
+
+	// Steve collections are listed by the Steve root, which sits alongside the v3 API on Rancher 2.5+
+	if version == "v3" && needsSteve(candidates, version, e.steve) {
+		var steveRoot apiRoot
+		if err := getJSON(serverRoot(e.rancherURL)+"/v1", e.accessKey, e.secretKey, &steveRoot); err != nil {
+			log.Warn("Failed to read the Rancher Steve API root: ", err)
+		}
+		for name, link := range steveRoot.collections() {
+			collections[name] = link
+		}
+	}
+
+	// Collectors already known to be disabled aren't warned about again when the API is probed again
+	previous := e.collectors
+	e.collectors = make(map[string]bool)
+	for _, endpoint := range candidates {
+		// Scoped endpoints are linked from each cluster or project, and the v1 root names its collections differently
+		_, linked := collections[collectionName(endpoint, steveEndpoint(endpoint, version, e.steve))]
+		available := linked || version == "v1" || clusterScoped[endpoint] || projectScoped[endpoint]
+
+		e.collectors[endpoint] = available
+		if !available {
+			if enabled, known := previous[endpoint]; !known || enabled {
+				log.Warnf("The Rancher API doesn't serve %s, its collector is disabled", endpoint)
+			}
+			continue
+		}
+		e.endpoints = append(e.endpoints, endpoint)
+	}
+
+	// The v1 collections are read in their v2-beta form, which the v1 root doesn't link to
+	if version != "v1" {
+		e.collections = collections
+	}
+
+	e.apiVersion = version
+	log.Infof("Detected Rancher API %s, collecting %s", version, strings.Join(e.endpoints, ", "))
+}
+
+// needsSteve - Checks whether any of the endpoints is read from the Steve API
+func needsSteve(candidates []string, version string, steve bool) bool {
+	for _, endpoint := range candidates {
+		if steveEndpoint(endpoint, version, steve) {
+			return true
+		}
+	}
+	return false
+}
+
+// steveEndpoint - Reports whether an endpoint is read from the Steve API. Besides the endpoints only Steve serves, that is every endpoint
+// when CATTLE_URL points at Steve, or those STEVE_API moves there from the v3 API.
+func steveEndpoint(endpoint string, version string, steve bool) bool {
+	switch {
+	case steveOnly[endpoint] || version == apiSteve:
+		return true
+	case version == "v3" && steve:
+		return steveMoved[endpoint]
+	}
+	return false
+}
+
+// collectionName - Returns the name an endpoint's collection is linked by, Steve schemas are singular while their collections are plural
+func collectionName(endpoint string, steve bool) string {
+	if steve {
+		return strings.TrimSuffix(steveKinds[endpoint], "s")
+	}
+	return endpoint
+}
+
+// collectionURL - Returns the URL of an endpoint's collection as linked from the API root, falling back to building it from CATTLE_URL
+func (e *Exporter) collectionURL(endpoint string, resourceLimit string) string {
+	steve := steveEndpoint(endpoint, e.apiVersion, e.steve)
+	if link, ok := e.collections[collectionName(endpoint, steve)]; ok {
+		return withLimit(link, resourceLimit)
+	}
+	if steve {
+		return serverRoot(e.rancherURL) + "/v1/" + steveKinds[endpoint] + "?limit=" + resourceLimit
+	}
+	return setEndpoint(e.rancherURL, endpoint, resourceLimit)
+}
+
+// withLimit - Adds the resource limit to a linked URL, which may already carry a query
+func withLimit(link string, resourceLimit string) string {
+	if strings.Contains(link, "?") {
+		return link + "&limit=" + resourceLimit
+	}
+	return link + "?limit=" + resourceLimit
+}
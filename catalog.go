@@ -0,0 +1,272 @@
+package main
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var versionSeparators = regexp.MustCompile(`[.\-_]`)
+
+// CatalogTemplate is a catalog template as listed by the v3 `templates` endpoint
+type CatalogTemplate struct {
+	ID               string            `json:"id"`
+	CatalogID        string            `json:"catalogId"`
+	ClusterCatalogID string            `json:"clusterCatalogId"`
+	ProjectCatalogID string            `json:"projectCatalogId"`
+	FolderName       string            `json:"folderName"`
+	DefaultVersion   string            `json:"defaultVersion"`
+	VersionLinks     map[string]string `json:"versionLinks"`
+}
+
+// catalogName returns the name of the catalog the template belongs to, without its namespace
+func (t *CatalogTemplate) catalogName() string {
+	for _, id := range []string{t.CatalogID, t.ClusterCatalogID, t.ProjectCatalogID} {
+		if id != "" {
+			return stripNamespace(id)
+		}
+	}
+	return ""
+}
+
+// latestVersion returns the newest version the template offers
+func (t *CatalogTemplate) latestVersion() string {
+	latest := t.DefaultVersion
+	for version := range t.VersionLinks {
+		if compareVersions(version, latest) > 0 {
+			latest = version
+		}
+	}
+	return latest
+}
+
+// V1Template is a template as returned by the Rancher 1.x catalog service, its version links end in the template revision
+type V1Template struct {
+	ID           string            `json:"id"`
+	VersionLinks map[string]string `json:"versionLinks"`
+	fetched      time.Time
+	missing      bool // The template couldn't be read and has never been read before
+}
+
+// revisions returns the template revision of every version, keyed by version
+func (t *V1Template) revisions() map[string]int {
+	revisions := make(map[string]int)
+	for version, link := range t.VersionLinks {
+		link = strings.SplitN(link, "?", 2)[0]
+		revision, err := strconv.Atoi(link[strings.LastIndex(link, ":")+1:])
+		if err != nil {
+			continue
+		}
+		revisions[version] = revision
+	}
+	return revisions
+}
+
+// catalogCache keeps catalog lookups between scrapes, catalogs change rarely and listing them is expensive
+type catalogCache struct {
+	mutex       sync.Mutex
+	ttl         time.Duration
+	fetched     time.Time
+	templates   []*CatalogTemplate
+	v1Templates map[string]*V1Template
+}
+
+func newCatalogCache(ttl time.Duration) *catalogCache {
+	return &catalogCache{
+		ttl:         ttl,
+		v1Templates: make(map[string]*V1Template),
+	}
+}
+
+// v1Template returns a template from the Rancher 1.x catalog service, fetching it again once the cache has expired.
+// Returns nil if the template has never been read successfully.
+func (c *catalogCache) v1Template(e *Exporter, catalog string, template string) *V1Template {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	id := catalog + ":" + template
+	cached, ok := c.v1Templates[id]
+	if !ok || time.Since(cached.fetched) >= c.ttl {
+		cached = c.fetchV1Template(e, id, cached)
+		c.v1Templates[id] = cached
+	}
+
+	if cached.missing {
+		return nil
+	}
+	return cached
+}
+
+// fetchV1Template reads a template from the Rancher 1.x catalog service. Failures are cached for as long as templates are,
+// so a removed template isn't requested again for every stack, and the previous template is kept until the next attempt.
+func (c *catalogCache) fetchV1Template(e *Exporter, id string, previous *V1Template) *V1Template {
+	t := new(V1Template)
+	url := serverRoot(e.rancherURL) + "/v1-catalog/templates/" + id
+	if err := getJSON(url, e.accessKey, e.secretKey, t); err != nil {
+		log.Warnf("Failed to read catalog template %s, retrying in %s: %s", id, c.ttl, err)
+		if previous == nil {
+			previous = &V1Template{ID: id, missing: true}
+		}
+		previous.fetched = time.Now()
+		return previous
+	}
+
+	t.fetched = time.Now()
+	return t
+}
+
+// v3Templates returns the v3 catalog templates, listing them again once the cache has expired
+func (c *catalogCache) v3Templates(e *Exporter) []*CatalogTemplate {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.templates != nil && time.Since(c.fetched) < c.ttl {
+		return c.templates
+	}
+
+	var data struct {
+		Data []*CatalogTemplate `json:"data"`
+	}
+	url := e.collectionURL("templates", templatesLimit)
+	if err := getJSON(url, e.accessKey, e.secretKey, &data); err != nil {
+		log.Warn("Failed to list catalog templates: ", err)
+		// Keep serving the previous templates until the next attempt
+		return c.templates
+	}
+
+	c.templates = data.Data
+	c.fetched = time.Now()
+	return c.templates
+}
+
+// findTemplate returns the v3 template for a catalog and template name, or nil if it isn't known
+func (c *catalogCache) findTemplate(e *Exporter, catalog string, template string) *CatalogTemplate {
+	for _, t := range c.v3Templates(e) {
+		if t.catalogName() == catalog && t.FolderName == template {
+			return t
+		}
+	}
+	return nil
+}
+
+// findTemplateVersion returns the v3 template and version a template version ID refers to, IDs take the form `<templateID>-<version>`
+func (c *catalogCache) findTemplateVersion(e *Exporter, templateVersionID string) (*CatalogTemplate, string) {
+	return matchTemplateVersion(c.v3Templates(e), templateVersionID)
+}
+
+// matchTemplateVersion splits a template version ID using the longest matching template ID,
+// so `library-mysql-operator-1.0.0` belongs to `library-mysql-operator` rather than `library-mysql`
+func matchTemplateVersion(templates []*CatalogTemplate, templateVersionID string) (*CatalogTemplate, string) {
+	var match *CatalogTemplate
+	for _, t := range templates {
+		if strings.HasPrefix(templateVersionID, t.ID+"-") && (match == nil || len(t.ID) > len(match.ID)) {
+			match = t
+		}
+	}
+	if match == nil {
+		return nil, ""
+	}
+	return match, strings.TrimPrefix(templateVersionID, match.ID+"-")
+}
+
+// parseAppExternalID splits a v3 app external ID such as `catalog://?catalog=library&template=mysql&version=1.6.2`
+func parseAppExternalID(externalID string) (catalog string, template string, version string) {
+	u, err := url.Parse(externalID)
+	if err != nil {
+		return "", "", ""
+	}
+	q := u.Query()
+	return stripNamespace(q.Get("catalog")), q.Get("template"), q.Get("version")
+}
+
+// parseStackExternalID splits a Rancher 1.x stack external ID such as `catalog://library:mysql:2` into catalog, template and revision
+func parseStackExternalID(externalID string) (catalog string, template string, revision int, ok bool) {
+	i := strings.Index(externalID, "catalog://")
+	if i < 0 {
+		return "", "", 0, false
+	}
+	parts := strings.Split(externalID[i+len("catalog://"):], ":")
+	if len(parts) != 3 {
+		return "", "", 0, false
+	}
+	revision, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return "", "", 0, false
+	}
+	return parts[0], parts[1], revision, true
+}
+
+// stackCatalogVersion works out the version a stack runs and whether a newer revision of its template exists
+func (e *Exporter) stackCatalogVersion(catalog string, template string, revision int) (version string, upgradeAvailable bool, ok bool) {
+	t := e.catalog.v1Template(e, catalog, template)
+	if t == nil {
+		return "", false, false
+	}
+
+	for v, r := range t.revisions() {
+		if r == revision {
+			version = v
+		}
+		if r > revision {
+			upgradeAvailable = true
+		}
+	}
+	return version, upgradeAvailable, true
+}
+
+// stripNamespace removes the `<namespace>:` prefix Rancher puts in front of IDs
+func stripNamespace(id string) string {
+	if i := strings.LastIndex(id, ":"); i >= 0 {
+		return id[i+1:]
+	}
+	return id
+}
+
+// compareVersions compares two version strings part by part, numerically where possible.
+// Build metadata after a `+` is ignored and missing parts count as zero, so `1.2` and `1.2.0+build1` are equal.
+// Returns a negative number if a is older than b, zero if they are equal and a positive number if a is newer.
+func compareVersions(a string, b string) int {
+	as := versionParts(a)
+	bs := versionParts(b)
+
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		ap, bp := "0", "0"
+		if i < len(as) {
+			ap = as[i]
+		}
+		if i < len(bs) {
+			bp = bs[i]
+		}
+
+		an, aErr := strconv.Atoi(ap)
+		bn, bErr := strconv.Atoi(bp)
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return an - bn
+			}
+		// A part that isn't a number marks a pre-release, which is older than the release itself
+		case aErr != nil && bErr == nil:
+			return -1
+		case aErr == nil && bErr != nil:
+			return 1
+		default:
+			if c := strings.Compare(ap, bp); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+// versionParts - Splits a version into its parts, dropping the `v` prefix and any build metadata
+func versionParts(version string) []string {
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.Index(version, "+"); i >= 0 {
+		version = version[:i]
+	}
+	return versionSeparators.Split(version, -1)
+}
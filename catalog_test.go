@@ -0,0 +1,139 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int // Sign of the result only
+	}{
+		{a: "1.0.0", b: "1.0.0", want: 0},
+		{a: "v1.0.0", b: "1.0.0", want: 0},
+		{a: "1.10.0", b: "1.9.0", want: 1},
+		{a: "1.9.0", b: "1.10.0", want: -1},
+		{a: "2.0.0", b: "1.99.99", want: 1},
+		// Partial versions are padded with zeros
+		{a: "1.2", b: "1.2.0", want: 0},
+		{a: "1.2.0", b: "1.2", want: 0},
+		{a: "1.2", b: "1.2.1", want: -1},
+		{a: "1.3", b: "1.2.9", want: 1},
+		{a: "1", b: "1.0.0", want: 0},
+		// Pre-releases are older than the release itself
+		{a: "1.0.0-rc1", b: "1.0.0", want: -1},
+		{a: "1.0.0", b: "1.0.0-rc1", want: 1},
+		{a: "1.0.0-rc1", b: "1.0.0-rc2", want: -1},
+		{a: "1.0-rc1", b: "1.0.0", want: -1},
+		{a: "1.0.0-rc1", b: "1.0", want: -1},
+		{a: "1.0.1-rc1", b: "1.0.0", want: 1},
+		// Build metadata doesn't take part in ordering
+		{a: "1.0.0+build1", b: "1.0.0", want: 0},
+		{a: "1.0.0", b: "1.0.0+build1", want: 0},
+		{a: "1.0.0+build1", b: "1.0.0+build2", want: 0},
+		{a: "1.0.1+build1", b: "1.0.0+build2", want: 1},
+		{a: "1.0.0-rc1+build1", b: "1.0.0", want: -1},
+		// Rancher chart versions often use underscores
+		{a: "0.1_2", b: "0.1_10", want: -1},
+	}
+
+	for _, tt := range tests {
+		got := compareVersions(tt.a, tt.b)
+		if sign(got) != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want sign %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+func TestParseStackExternalID(t *testing.T) {
+	tests := []struct {
+		externalID string
+		catalog    string
+		template   string
+		revision   int
+		ok         bool
+	}{
+		{externalID: "catalog://library:mysql:2", catalog: "library", template: "mysql", revision: 2, ok: true},
+		{externalID: "system://catalog://library:infra*network-services:20", catalog: "library", template: "infra*network-services", revision: 20, ok: true},
+		{externalID: "catalog://library:mysql", ok: false},
+		{externalID: "catalog://library:mysql:latest", ok: false},
+		{externalID: "catalog://library:mysql:2:extra", ok: false},
+		{externalID: "", ok: false},
+		{externalID: "library:mysql:2", ok: false},
+	}
+
+	for _, tt := range tests {
+		catalog, template, revision, ok := parseStackExternalID(tt.externalID)
+		if ok != tt.ok {
+			t.Errorf("parseStackExternalID(%q) ok = %v, want %v", tt.externalID, ok, tt.ok)
+			continue
+		}
+		if catalog != tt.catalog || template != tt.template || revision != tt.revision {
+			t.Errorf("parseStackExternalID(%q) = %q, %q, %d, want %q, %q, %d",
+				tt.externalID, catalog, template, revision, tt.catalog, tt.template, tt.revision)
+		}
+	}
+}
+
+func TestParseAppExternalID(t *testing.T) {
+	tests := []struct {
+		externalID string
+		catalog    string
+		template   string
+		version    string
+	}{
+		{externalID: "catalog://?catalog=library&template=mysql&version=1.6.2", catalog: "library", template: "mysql", version: "1.6.2"},
+		// Project and cluster catalogs are prefixed with their namespace
+		{externalID: "catalog://?catalog=p-abcde/my-charts&type=projectCatalog&template=app&version=0.1.0", catalog: "p-abcde/my-charts", template: "app", version: "0.1.0"},
+		{externalID: "catalog://?catalog=c-abcde:my-charts&template=app&version=0.1.0", catalog: "my-charts", template: "app", version: "0.1.0"},
+		{externalID: "catalog://?catalog=library&template=mysql", catalog: "library", template: "mysql", version: ""},
+		{externalID: "", catalog: "", template: "", version: ""},
+		{externalID: "%zz", catalog: "", template: "", version: ""},
+	}
+
+	for _, tt := range tests {
+		catalog, template, version := parseAppExternalID(tt.externalID)
+		if catalog != tt.catalog || template != tt.template || version != tt.version {
+			t.Errorf("parseAppExternalID(%q) = %q, %q, %q, want %q, %q, %q",
+				tt.externalID, catalog, template, version, tt.catalog, tt.template, tt.version)
+		}
+	}
+}
+
+func TestMatchTemplateVersion(t *testing.T) {
+	templates := []*CatalogTemplate{
+		{ID: "cattle-global-data:library-mysql"},
+		{ID: "cattle-global-data:library-mysql-operator"},
+	}
+
+	tests := []struct {
+		templateVersionID string
+		templateID        string
+		version           string
+	}{
+		{templateVersionID: "cattle-global-data:library-mysql-1.6.2", templateID: "cattle-global-data:library-mysql", version: "1.6.2"},
+		{templateVersionID: "cattle-global-data:library-mysql-operator-1.0.0", templateID: "cattle-global-data:library-mysql-operator", version: "1.0.0"},
+		{templateVersionID: "cattle-global-data:library-redis-1.0.0", templateID: "", version: ""},
+	}
+
+	for _, tt := range tests {
+		template, version := matchTemplateVersion(templates, tt.templateVersionID)
+		var templateID string
+		if template != nil {
+			templateID = template.ID
+		}
+		if templateID != tt.templateID || version != tt.version {
+			t.Errorf("matchTemplateVersion(%q) = %q, %q, want %q, %q", tt.templateVersionID, templateID, version, tt.templateID, tt.version)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metricName matches valid Prometheus metric and label names
+var metricName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// CustomMetricsConfig is the file named by CUSTOM_METRICS_CONFIG
+type CustomMetricsConfig struct {
+	Metrics []CustomMetric `json:"metrics"`
+}
+
+// CustomMetric declares a metric read from every item of a Rancher API collection.
+// Fields are addressed by dotted paths such as `status.readyReplicas`, numeric parts index into lists.
+type CustomMetric struct {
+	Name       string            `json:"name"`       // Exported with the `rancher_` prefix
+	Help       string            `json:"help"`       // Help text of the metric
+	Type       string            `json:"type"`       // Either `gauge` (default) or `counter`
+	Collection string            `json:"collection"` // Collection linked from the API root, Steve type, URL or path relative to CATTLE_URL
+	Value      string            `json:"value"`      // Path of the value, every item counts as 1 when empty
+	Labels     map[string]string `json:"labels"`     // Label names and the paths of their values
+}
+
+// customMetric is a CustomMetric ready to be collected
+type customMetric struct {
+	CustomMetric
+	desc       *prometheus.Desc
+	valueType  prometheus.ValueType
+	labelNames []string
+}
+
+// customDesc describes a custom metric on its own, used to check it can be registered alongside the built-in metrics
+type customDesc struct {
+	desc *prometheus.Desc
+}
+
+// Describe sends the description of the custom metric
+func (d customDesc) Describe(ch chan<- *prometheus.Desc) {
+	ch <- d.desc
+}
+
+// Collect sends nothing, the metric is only described
+func (d customDesc) Collect(ch chan<- prometheus.Metric) {}
+
+// loadCustomMetrics - Reads and validates the custom metrics config file
+func loadCustomMetrics(path string) ([]*customMetric, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var config CustomMetricsConfig
+	if err := json.NewDecoder(f).Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %s", path, err)
+	}
+
+	// Clashing metrics would make registering the exporter panic, they are checked against the built-in metrics up front
+	registry := prometheus.NewRegistry()
+	for _, g := range addMetrics() {
+		registry.MustRegister(g)
+	}
+
+	var metrics []*customMetric
+	names := make(map[string]bool)
+	for _, m := range config.Metrics {
+		if !metricName.MatchString(m.Name) {
+			return nil, fmt.Errorf("invalid metric name %q", m.Name)
+		}
+		if names[m.Name] {
+			return nil, fmt.Errorf("metric %s is declared more than once", m.Name)
+		}
+		names[m.Name] = true
+		if m.Collection == "" {
+			return nil, fmt.Errorf("metric %s has no collection", m.Name)
+		}
+
+		c := &customMetric{CustomMetric: m}
+		switch m.Type {
+		case "", "gauge":
+			c.valueType = prometheus.GaugeValue
+		case "counter":
+			c.valueType = prometheus.CounterValue
+		default:
+			return nil, fmt.Errorf("metric %s has unknown type %q, expected gauge or counter", m.Name, m.Type)
+		}
+
+		for label := range m.Labels {
+			if !metricName.MatchString(label) {
+				return nil, fmt.Errorf("metric %s has invalid label name %q", m.Name, label)
+			}
+			c.labelNames = append(c.labelNames, label)
+		}
+		// Sort for same order
+		sort.Strings(c.labelNames)
+
+		c.desc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", m.Name), m.Help, c.labelNames, nil)
+		if err := registry.Register(customDesc{c.desc}); err != nil {
+			return nil, fmt.Errorf("metric %s clashes with a built-in metric: %s", m.Name, err)
+		}
+		metrics = append(metrics, c)
+	}
+
+	return metrics, nil
+}
+
+// collectCustomMetrics - Fetches the collection of every custom metric and sends a sample per item, skipping any that fail
+func (e *Exporter) collectCustomMetrics(ch chan<- prometheus.Metric) {
+	for _, m := range e.customMetrics {
+		var data struct {
+			Data []interface{} `json:"data"`
+		}
+		url := withLimit(e.customCollectionURL(m.Collection), e.resourceLimit)
+		if err := getJSON(url, e.accessKey, e.secretKey, &data); err != nil {
+			log.Warnf("Error getting JSON from collection %s for custom metric %s", m.Collection, m.Name)
+			continue
+		}
+
+		// Items resolving to the same labels would be rejected by the registry, only the first is kept
+		seen := make(map[string]bool)
+		for _, item := range data.Data {
+			value := 1.0
+			if m.Value != "" {
+				field, ok := lookupField(item, m.Value)
+				if !ok {
+					continue
+				}
+				if value, ok = fieldValue(field); !ok {
+					log.Debugf("Custom metric %s has a value of %v which isn't a number", m.Name, field)
+					continue
+				}
+			}
+
+			labelValues := make([]string, len(m.labelNames))
+			for i, label := range m.labelNames {
+				field, _ := lookupField(item, m.Labels[label])
+				labelValues[i] = labelValue(field)
+			}
+
+			key := strings.Join(labelValues, "\xff")
+			if seen[key] {
+				log.Warnf("Custom metric %s has more than one item labelled %v, only the first is exported", m.Name, labelValues)
+				continue
+			}
+			seen[key] = true
+
+			ch <- prometheus.MustNewConstMetric(m.desc, m.valueType, value, labelValues...)
+		}
+	}
+}
+
+// customCollectionURL - Resolves the collection of a custom metric, preferring the links read from the API root
+func (e *Exporter) customCollectionURL(collection string) string {
+	if strings.HasPrefix(collection, "http://") || strings.HasPrefix(collection, "https://") {
+		return collection
+	}
+	if link, ok := e.collections[strings.ToLower(collection)]; ok {
+		return link
+	}
+
+	// Steve types such as `apps.deployments` live under /v1 of the server, their schemas are singular
+	if strings.Contains(collection, ".") {
+		if link, ok := e.collections[strings.TrimSuffix(collection, "s")]; ok {
+			return link
+		}
+		return serverRoot(e.rancherURL) + "/v1/" + collection
+	}
+
+	return strings.TrimSuffix(e.rancherURL, "/") + "/" + strings.TrimPrefix(collection, "/")
+}
+
+// lookupField - Follows a dotted path through decoded JSON, reports false if any part of it is missing
+func lookupField(item interface{}, path string) (interface{}, bool) {
+	for _, key := range strings.Split(path, ".") {
+		switch v := item.(type) {
+		case map[string]interface{}:
+			field, ok := v[key]
+			if !ok {
+				return nil, false
+			}
+			item = field
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			item = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return item, true
+}
+
+// fieldValue - Converts a JSON value into a sample, booleans count as 1 or 0 and strings may be quantities or timestamps
+func fieldValue(field interface{}) (float64, bool) {
+	switch v := field.(type) {
+	case float64:
+		return v, true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	case string:
+		if q, err := parseQuantity(v); err == nil {
+			return q, true
+		}
+		return parseTimestamp(v)
+	}
+	return 0, false
+}
+
+// labelValue - Formats a JSON value as a label, missing values and objects are left empty
+func labelValue(field interface{}) string {
+	switch v := field.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
@@ -3,32 +3,67 @@ package main
 import (
 	"regexp"
 	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 // Exporter Sets up all the runtime and metrics
 type Exporter struct {
-	labelsFilter  *regexp.Regexp
-	rancherURL    string
-	accessKey     string
-	secretKey     string
-	hideSys       bool
-	resourceLimit string
-	mutex         sync.RWMutex
-	gaugeVecs     map[string]*prometheus.GaugeVec
+	labelsFilter    *regexp.Regexp
+	rancherURL      string
+	accessKey       string
+	secretKey       string
+	hideSys         bool
+	resourceLimit   string
+	mountFilter     mountFilter
+	legacyHostMB    bool
+	namespaceFilter namespaceFilter
+	podsPerPod      bool
+	catalog         *catalogCache
+	clusterProbe    clusterProbe
+	steve           bool
+	apiVersion      string
+	endpoints       []string
+	collections     map[string]string
+	collectors      map[string]bool
+	customMetrics   []*customMetric
+	mutex           sync.RWMutex
+	gaugeVecs       map[string]*prometheus.GaugeVec
+}
+
+// mountFilter holds the optional regular expressions used to filter host mount points, a nil value matches everything
+type mountFilter struct {
+	mountPointInclude *regexp.Regexp
+	mountPointExclude *regexp.Regexp
+	fsTypeInclude     *regexp.Regexp
+	fsTypeExclude     *regexp.Regexp
+}
+
+// namespaceFilter holds the optional regular expressions used to filter Kubernetes namespaces, a nil value matches everything
+type namespaceFilter struct {
+	include *regexp.Regexp
+	exclude *regexp.Regexp
 }
 
 // NewExporter creates the metrics we wish to monitor
-func newExporter(rancherURL, accessKey, secretKey string, labelsFilter *regexp.Regexp, hideSys bool, resourceLimit string) *Exporter {
+func newExporter(rancherURL, accessKey, secretKey string, labelsFilter *regexp.Regexp, hideSys bool, resourceLimit string, mountFilter mountFilter, legacyHostMB bool, namespaceFilter namespaceFilter, podsPerPod bool, catalogTTL time.Duration, clusterProbe clusterProbe, steve bool, customMetrics []*customMetric) *Exporter {
 	gaugeVecs := addMetrics()
 	return &Exporter{
-		labelsFilter:  labelsFilter,
-		gaugeVecs:     gaugeVecs,
-		rancherURL:    rancherURL,
-		accessKey:     accessKey,
-		secretKey:     secretKey,
-		hideSys:       hideSys,
-		resourceLimit: resourceLimit,
+		labelsFilter:    labelsFilter,
+		gaugeVecs:       gaugeVecs,
+		rancherURL:      rancherURL,
+		accessKey:       accessKey,
+		secretKey:       secretKey,
+		hideSys:         hideSys,
+		resourceLimit:   resourceLimit,
+		mountFilter:     mountFilter,
+		legacyHostMB:    legacyHostMB,
+		namespaceFilter: namespaceFilter,
+		podsPerPod:      podsPerPod,
+		catalog:         newCatalogCache(catalogTTL),
+		clusterProbe:    clusterProbe,
+		steve:           steve,
+		customMetrics:   customMetrics,
 	}
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -13,47 +14,243 @@ import (
 
 // Data is used to store data from all the relevant endpoints in the API
 type Data struct {
-	Data []struct {
-		HealthState string            `json:"healthState"`
-		Name        string            `json:"name"`
-		State       string            `json:"state"`
-		System      bool              `json:"system"`
-		Scale       int               `json:"scale"`
-		HostName    string            `json:"hostname"`
-		ID          string            `json:"id"`
-		StackID     string            `json:"stackId"`
-		EnvID       string            `json:"environmentId"`
-		BaseType    string            `json:"basetype"`
-		Type        string            `json:"type"`
-		AgentState  string            `json:"agentState"`
-		Labels      map[string]string `json:"labels"`
-		ClusterID   string            `json:"clusterId"`
-		NodeName    string            `json:"nodeName"`
-		// HostInfo for hosts
-		HostInfo *HostInfo `json:"info"`
-		// LaunchConfig for services
-		LaunchConfig *LaunchConfig `json:"launchConfig"`
-		// ComponentStatuses for clusters
-		ComponentStatuses []*ComponentStatuses `json:"componentStatuses"`
-	} `json:"data"`
+	Data []Resource `json:"data"`
+}
+
+// RawData is a collection as returned by the API, its items are decoded one at a time by decodeResources
+type RawData struct {
+	Data []json.RawMessage `json:"data"`
+}
+
+// Resource is a single item of an endpoint, holding the fields read from every kind of resource
+type Resource struct {
+	HealthState string            `json:"healthState"`
+	Name        string            `json:"name"`
+	State       string            `json:"state"`
+	System      bool              `json:"system"`
+	Scale       int               `json:"scale"`
+	HostName    string            `json:"hostname"`
+	ID          string            `json:"id"`
+	StackID     string            `json:"stackId"`
+	EnvID       string            `json:"environmentId"`
+	BaseType    string            `json:"basetype"`
+	Type        string            `json:"type"`
+	AgentState  string            `json:"agentState"`
+	Labels      map[string]string `json:"labels"`
+	ClusterID   string            `json:"clusterId"`
+	NodeName    string            `json:"nodeName"`
+	// HostInfo for hosts
+	HostInfo *HostInfo `json:"info"`
+	// LaunchConfig for services
+	LaunchConfig *LaunchConfig `json:"launchConfig"`
+	// ComponentStatuses and versions for clusters, the Kubernetes version is decoded separately as other kinds use `version` differently
+	ComponentStatuses []*ComponentStatuses `json:"componentStatuses"`
+	Version           *KubernetesVersion   `json:"-"`
+	Provider          string               `json:"provider"`
+	Driver            string               `json:"driver"`
+	AgentImage        string               `json:"agentImage"`
+	RKEConfig         *RKEConfig           `json:"rancherKubernetesEngineConfig"`
+	// CertificatesExpiration for RKE clusters, keyed by component
+	CertificatesExpiration map[string]CertificateExpiration `json:"certificatesExpiration"`
+	// Resource quantities for clusters and nodes
+	Capacity    map[string]string `json:"capacity"`
+	Allocatable map[string]string `json:"allocatable"`
+	Requested   map[string]string `json:"requested"`
+	Limits      map[string]string `json:"limits"`
+	// Conditions, roles and scheduling for nodes
+	Conditions    []*Condition `json:"conditions"`
+	Etcd          bool         `json:"etcd"`
+	ControlPlane  bool         `json:"controlPlane"`
+	Worker        bool         `json:"worker"`
+	Unschedulable bool         `json:"unschedulable"`
+	Taints        []*Taint     `json:"taints"`
+	// Node pool membership, sizing and templates
+	NodePoolID     string `json:"nodePoolId"`
+	HostnamePrefix string `json:"hostnamePrefix"`
+	Quantity       int    `json:"quantity"`
+	NodeTemplateID string `json:"nodeTemplateId"`
+	// Project membership and quotas for projects and namespaces
+	ProjectID     string         `json:"projectId"`
+	ResourceQuota *ResourceQuota `json:"resourceQuota"`
+	// Namespace and replica statuses for workloads
+	NamespaceID       string          `json:"namespaceId"`
+	DeploymentStatus  *WorkloadStatus `json:"deploymentStatus"`
+	DaemonSetStatus   *WorkloadStatus `json:"daemonSetStatus"`
+	StatefulSetStatus *WorkloadStatus `json:"statefulSetStatus"`
+	// Phase, workload and containers for pods, the phase is decoded separately as other kinds use `status` differently
+	PodStatus  *PodStatus   `json:"-"`
+	WorkloadID string       `json:"workloadId"`
+	Containers []*Container `json:"containers"`
+	// Catalog details for stacks, apps and multi-cluster apps
+	ExternalID        string `json:"externalId"`
+	TargetNamespace   string `json:"targetNamespace"`
+	TemplateVersionID string `json:"templateVersionId"`
+	// Creation time for etcd backups and cluster scans
+	Created string `json:"created"`
+	// Profile and results for cluster scans
+	ScanConfig    *ScanConfig    `json:"scanConfig"`
+	CisScanStatus *CisScanStatus `json:"cisScanStatus"`
+	// Common name, expiry and environment ID for certificates
+	CN        string `json:"cn"`
+	ExpiresAt string `json:"expiresAt"`
+	AccountID string `json:"accountId"`
+	// Expiry and ownership for tokens, users and global role bindings, token values are never read
+	Expired      bool   `json:"expired"`
+	Current      bool   `json:"current"`
+	Enabled      *bool  `json:"enabled"`
+	UserID       string `json:"userId"`
+	GlobalRoleID string `json:"globalRoleId"`
+	// Source, readiness and deployment summaries for Fleet GitRepos and bundles
+	Repo                 string         `json:"repo"`
+	Branch               string         `json:"branch"`
+	Commit               string         `json:"commit"`
+	ReadyClusters        int            `json:"readyClusters"`
+	DesiredReadyClusters int            `json:"desiredReadyClusters"`
+	Summary              *BundleSummary `json:"summary"`
+	// Snapshot times and retention for rancher-backup backups and restores, decoded separately from the Steve API
+	LastSnapshotTS      string `json:"-"`
+	RetentionCount      int    `json:"-"`
+	RestoreCompletionTS string `json:"-"`
+	// Transitioning is `error` when Rancher reports the resource as failed
+	Transitioning string `json:"transitioning"`
+	// Links to the collections nested under clusters and projects
+	Links map[string]string `json:"links"`
 }
 
 type HostInfo struct {
 	CPUInfo struct {
-		Count int `json:"count"`
+		Count               int       `json:"count"`
+		LoadAvg             []float64 `json:"loadAvg"`
+		CPUCoresPercentages []float64 `json:"cpuCoresPercentages"`
 	} `json:"cpuInfo"`
 	MemoryInfo struct {
-		MemTotal int `json:"memTotal"`
-		MemFree  int `json:"memFree"`
+		MemTotal float64 `json:"memTotal"`
+		MemFree  float64 `json:"memFree"`
 	} `json:"memoryInfo"`
 	DiskInfo struct {
 		MountPoints map[string]MountPoint `json:"mountPoints"`
 	} `json:"diskInfo"`
+	OSInfo struct {
+		DockerVersion   string `json:"dockerVersion"`
+		KernelVersion   string `json:"kernelVersion"`
+		OperatingSystem string `json:"operatingSystem"`
+	} `json:"osInfo"`
+	// OS and Kubernetes are only reported for v3 nodes
+	OS struct {
+		DockerVersion   string `json:"dockerVersion"`
+		KernelVersion   string `json:"kernelVersion"`
+		OperatingSystem string `json:"operatingSystem"`
+	} `json:"os"`
+	Kubernetes struct {
+		KubeletVersion string `json:"kubeletVersion"`
+	} `json:"kubernetes"`
 }
 
+// MountPoint sizes are reported by the agent in MB (10^6 bytes), keyed by device name
 type MountPoint struct {
-	Total int `json:"total"`
-	Used  int `json:"used"`
+	Total float64 `json:"total"`
+	Used  float64 `json:"used"`
+}
+
+type KubernetesVersion struct {
+	GitVersion string `json:"gitVersion"`
+}
+
+// clusterDetails holds the cluster fields other kinds share a name with but not a type
+type clusterDetails struct {
+	Version *KubernetesVersion `json:"version"`
+}
+
+// RKEConfig holds the parts of an RKE cluster's configuration the exporter reads
+type RKEConfig struct {
+	Services struct {
+		Etcd struct {
+			// Creation is the snapshot interval used before Rancher 2.2, e.g. `12h`
+			Creation     string `json:"creation"`
+			BackupConfig *struct {
+				Enabled       *bool `json:"enabled"`
+				IntervalHours int   `json:"intervalHours"`
+			} `json:"backupConfig"`
+		} `json:"etcd"`
+	} `json:"services"`
+}
+
+// etcdBackupInterval returns the configured time between etcd snapshots, or zero if recurring snapshots are disabled
+func (c *RKEConfig) etcdBackupInterval() time.Duration {
+	etcd := c.Services.Etcd
+	if etcd.BackupConfig != nil {
+		if etcd.BackupConfig.Enabled != nil && !*etcd.BackupConfig.Enabled {
+			return 0
+		}
+		return time.Duration(etcd.BackupConfig.IntervalHours) * time.Hour
+	}
+	interval, err := time.ParseDuration(etcd.Creation)
+	if err != nil {
+		return 0
+	}
+	return interval
+}
+
+// etcdBackupSummary holds the backups of one cluster, built up while processing the etcdbackups endpoint
+type etcdBackupSummary struct {
+	total       int
+	failed      int
+	lastSuccess float64
+}
+
+type CertificateExpiration struct {
+	ExpirationDate string `json:"expirationDate"`
+}
+
+// securitySummary holds the token and user counts, built up while processing the security endpoints
+type securitySummary struct {
+	tokens            int
+	expiredTokens     int
+	nonExpiringTokens int
+	ownTokenExpiry    float64
+	ownTokenExpires   bool
+	users             int
+	enabledUsers      int
+	globalAdmins      map[string]bool
+}
+
+// nodeTemplate holds the name and machine driver of a node template, used to label node pools
+type nodeTemplate struct {
+	name   string
+	driver string
+}
+
+type ScanConfig struct {
+	CisScanConfig *struct {
+		Profile string `json:"profile"`
+	} `json:"cisScanConfig"`
+}
+
+type CisScanStatus struct {
+	Total         int `json:"total"`
+	Pass          int `json:"pass"`
+	Fail          int `json:"fail"`
+	Skip          int `json:"skip"`
+	NotApplicable int `json:"notApplicable"`
+}
+
+// clusterScan holds the latest completed CIS scan of a cluster, found while processing the clusterscans endpoint
+type clusterScan struct {
+	created float64
+	profile string
+	status  *CisScanStatus
+}
+
+// BundleSummary counts the deployments of a Fleet bundle, or of every bundle of a GitRepo, by state
+type BundleSummary struct {
+	DesiredReady int `json:"desiredReady"`
+	Ready        int `json:"ready"`
+	NotReady     int `json:"notReady"`
+	WaitApplied  int `json:"waitApplied"`
+	ErrApplied   int `json:"errApplied"`
+	OutOfSync    int `json:"outOfSync"`
+	Modified     int `json:"modified"`
+	Pending      int `json:"pending"`
 }
 
 type LaunchConfig struct {
@@ -66,13 +263,114 @@ type ComponentStatuses struct {
 }
 
 type Condition struct {
-	Status string `json:"status"`
+	Type               string `json:"type"`
+	Status             string `json:"status"`
+	LastTransitionTime string `json:"lastTransitionTime"`
+}
+
+type ResourceQuota struct {
+	Limit     map[string]string `json:"limit"`
+	UsedLimit map[string]string `json:"usedLimit"`
+}
+
+// WorkloadStatus holds the replica counts of deployments and statefulsets, or the scheduling counts of daemonsets
+type WorkloadStatus struct {
+	ReadyReplicas          int `json:"readyReplicas"`
+	AvailableReplicas      int `json:"availableReplicas"`
+	UnavailableReplicas    int `json:"unavailableReplicas"`
+	DesiredNumberScheduled int `json:"desiredNumberScheduled"`
+	NumberReady            int `json:"numberReady"`
+	NumberAvailable        int `json:"numberAvailable"`
+	NumberUnavailable      int `json:"numberUnavailable"`
+}
+
+// WorkloadReplicas are the replica counts of a workload, normalised across workload types
+type WorkloadReplicas struct {
+	Desired     int
+	Ready       int
+	Available   int
+	Unavailable int
+}
+
+// newWorkloadReplicas - Reads the replica counts from the status matching the workload type, returns nil for types without replicas
+func newWorkloadReplicas(workloadType string, scale int, deployment, daemonSet, statefulSet *WorkloadStatus) *WorkloadReplicas {
+	switch {
+	case workloadType == "deployment" && deployment != nil:
+		return &WorkloadReplicas{
+			Desired:     scale,
+			Ready:       deployment.ReadyReplicas,
+			Available:   deployment.AvailableReplicas,
+			Unavailable: deployment.UnavailableReplicas,
+		}
+	case workloadType == "daemonSet" && daemonSet != nil:
+		return &WorkloadReplicas{
+			Desired:     daemonSet.DesiredNumberScheduled,
+			Ready:       daemonSet.NumberReady,
+			Available:   daemonSet.NumberAvailable,
+			Unavailable: daemonSet.NumberUnavailable,
+		}
+	case workloadType == "statefulSet" && statefulSet != nil:
+		// Statefulsets have no notion of availability, a ready replica is an available one
+		unavailable := scale - statefulSet.ReadyReplicas
+		if unavailable < 0 {
+			unavailable = 0
+		}
+		return &WorkloadReplicas{
+			Desired:     scale,
+			Ready:       statefulSet.ReadyReplicas,
+			Available:   statefulSet.ReadyReplicas,
+			Unavailable: unavailable,
+		}
+	}
+	return nil
+}
+
+type PodStatus struct {
+	Phase string `json:"phase"`
+}
+
+// podDetails holds the pod fields other kinds share a name with but not a type
+type podDetails struct {
+	Status *PodStatus `json:"status"`
+}
+
+type Container struct {
+	Name         string `json:"name"`
+	RestartCount int    `json:"restartCount"`
+}
+
+type Taint struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Effect string `json:"effect"`
 }
 
 // processMetrics - Collects the data from the API, returns data object
 func (e *Exporter) processMetrics(data *Data, endpoint string, hideSys bool, ch chan<- prometheus.Metric) error {
 	var filteredLabels map[string]string
 
+	// Backups, scans, tokens and users are summarised once every item has been seen
+	etcdBackups := make(map[string]*etcdBackupSummary)
+	clusterScans := make(map[string]*clusterScan)
+	clusters := make(map[string]string)
+	var security securitySummary
+
+	// Clusters, projects, node pools and node templates are stored afresh every scrape,
+	// so deleted ones are no longer scraped for their nested collections or used as labels
+	switch endpoint {
+	case "clusters":
+		clusterRef = make(map[string]string)
+		clusterVersions = make(map[string]string)
+		clusterLinks = make(map[string]map[string]string)
+	case "nodetemplates":
+		nodeTemplates = make(map[string]nodeTemplate)
+	case "nodepools":
+		nodePoolRef = make(map[string]string)
+	case "projects":
+		projectRef = make(map[string]string)
+		projectLinks = make(map[string]map[string]string)
+	}
+
 	// Metrics - range through the data object
 	for _, x := range data.Data {
 		// If system services have been ignored, the loop simply skips them
@@ -99,7 +397,7 @@ func (e *Exporter) processMetrics(data *Data, endpoint string, hideSys bool, ch
 			}
 			e.setHostStateMetrics(s, x.State, x.AgentState, filteredLabels)
 			if x.HostInfo != nil {
-				e.setHostInfoMetrics(s, x.HostInfo, filteredLabels)
+				e.setHostInfoMetrics(s, x.HostInfo, x.Labels[agentImageLabel], filteredLabels)
 			}
 		} else if endpoint == "stacks" {
 			// Used to create a map of stackID and stackName
@@ -107,6 +405,12 @@ func (e *Exporter) processMetrics(data *Data, endpoint string, hideSys bool, ch
 			stackRef = storeStackRef(x.ID, x.Name)
 
 			e.setStackMetrics(x.Name, x.State, x.HealthState, strconv.FormatBool(x.System))
+
+			// Stacks launched from a catalog are compared against the newest revision of their template
+			if catalog, template, revision, ok := parseStackExternalID(x.ExternalID); ok {
+				version, upgradeAvailable, found := e.stackCatalogVersion(catalog, template, revision)
+				e.setStackCatalogMetrics(x.Name, catalog, template, version, upgradeAvailable, found)
+			}
 		} else if endpoint == "services" {
 			// Retrieves the stack Name from the previous values stored.
 			var stackName = retrieveStackRef(x.StackID)
@@ -120,9 +424,31 @@ func (e *Exporter) processMetrics(data *Data, endpoint string, hideSys bool, ch
 			}
 
 			e.setServiceMetrics(x.Name, stackName, x.State, x.HealthState, x.Scale, filteredLabels)
+		} else if endpoint == "certificates" {
+			e.setCertificateExpiryMetrics(prometheus.Labels{"environment_id": x.AccountID, "name": x.Name, "cn": x.CN}, x.ExpiresAt)
 		} else if endpoint == "clusters" {
 			clusterRef = storeClusterRef(x.ID, x.Name)
+			clusterLinks = storeResourceLinks(clusterLinks, x.ID, x.Links)
+			clusters[x.ID] = x.Name
+
+			var kubernetesVersion string
+			if x.Version != nil {
+				kubernetesVersion = x.Version.GitVersion
+			}
+			clusterVersions[x.ID] = kubernetesVersion
+
 			e.setClusterMetrics(x.Name, x.State, x.ComponentStatuses)
+			e.setClusterInfoMetrics(x.Name, kubernetesVersion, x.Provider, x.Driver, x.AgentImage)
+			e.setClusterConditionMetrics(x.Name, x.Conditions)
+			e.setClusterResourceMetrics(x.Name, x.Capacity, x.Allocatable, x.Requested, x.Limits)
+
+			if x.RKEConfig != nil {
+				e.setClusterEtcdBackupInterval(x.Name, x.RKEConfig.etcdBackupInterval())
+			}
+
+			for component, expiration := range x.CertificatesExpiration {
+				e.setCertificateExpiryMetrics(prometheus.Labels{"cluster": x.Name, "component": component}, expiration.ExpirationDate)
+			}
 		} else if endpoint == "nodes" {
 			// Retrieves the cluster Name from the previous values stored.
 			var clusterName = retrieveClusterRef(x.ClusterID)
@@ -132,7 +458,234 @@ func (e *Exporter) processMetrics(data *Data, endpoint string, hideSys bool, ch
 			}
 
 			e.setNodeMetrics(x.NodeName, x.State, clusterName)
+			e.setNodeResourceMetrics(x.NodeName, clusterName, x.Capacity, x.Allocatable, x.Requested, x.Limits)
+
+			roles := map[string]bool{
+				"etcd":         x.Etcd,
+				"controlplane": x.ControlPlane,
+				"worker":       x.Worker,
+			}
+			e.setNodeStatusMetrics(x.NodeName, clusterName, x.Conditions, roles, x.Unschedulable, x.Taints)
+
+			if x.HostInfo != nil {
+				e.setNodeInfoMetrics(x.NodeName, clusterName, x.HostInfo, clusterVersions[x.ClusterID])
+			}
+
+			// Nodes created by a node pool are counted against it
+			if pool, ok := nodePoolRef[x.NodePoolID]; ok && x.NodePoolID != "" {
+				e.countNodePoolNode(pool, clusterName, x.State)
+			}
+		} else if endpoint == "nodetemplates" {
+			nodeTemplates[x.ID] = nodeTemplate{name: x.Name, driver: x.Driver}
+		} else if endpoint == "nodepools" {
+			// Used to create a map of nodePoolID and hostname prefix
+			// Later used as a dimension in node pool metrics
+			nodePoolRef[x.ID] = x.HostnamePrefix
+
+			var clusterName = retrieveClusterRef(x.ClusterID)
+			var template = nodeTemplates[x.NodeTemplateID]
+
+			e.setNodePoolMetrics(x.HostnamePrefix, clusterName, template.name, template.driver, x.Quantity)
+		} else if endpoint == "projects" {
+			// Used to create a map of projectID and projectName
+			// Later used as a dimension in namespace metrics
+			projectRef = storeProjectRef(x.ID, x.Name)
+			projectLinks = storeResourceLinks(projectLinks, x.ID, x.Links)
+
+			if hideSys && x.Name == systemProject {
+				continue
+			}
+
+			var clusterName = retrieveClusterRef(x.ClusterID)
+
+			e.setProjectMetrics(x.Name, x.State, clusterName, x.ResourceQuota)
+		} else if endpoint == "namespaces" {
+			var projectName = retrieveProjectRef(x.ProjectID)
+
+			if hideSys && (strings.HasPrefix(x.Name, systemNamespacePrefix) || projectName == systemProject) {
+				continue
+			}
+
+			// Namespaces that haven't been moved into a project are not counted
+			if x.ProjectID == "" {
+				continue
+			}
+
+			var clusterName = retrieveClusterRef(clusterIDOf(x.ProjectID))
+
+			e.countProjectNamespace(projectName, clusterName)
+		} else if endpoint == "workloads" {
+			var projectName = retrieveProjectRef(x.ProjectID)
+
+			if hideSys && (strings.HasPrefix(x.NamespaceID, systemNamespacePrefix) || projectName == systemProject) {
+				continue
+			}
+
+			if !e.allowedNamespace(x.NamespaceID) {
+				continue
+			}
+
+			var clusterName = retrieveClusterRef(clusterIDOf(x.ProjectID))
+			var replicas = newWorkloadReplicas(x.Type, x.Scale, x.DeploymentStatus, x.DaemonSetStatus, x.StatefulSetStatus)
+
+			e.setWorkloadMetrics(x.Name, x.Type, x.NamespaceID, projectName, clusterName, x.State, replicas)
+		} else if endpoint == "pods" {
+			var projectName = retrieveProjectRef(x.ProjectID)
+
+			if hideSys && (strings.HasPrefix(x.NamespaceID, systemNamespacePrefix) || projectName == systemProject) {
+				continue
+			}
+
+			if !e.allowedNamespace(x.NamespaceID) {
+				continue
+			}
+
+			var clusterName = retrieveClusterRef(clusterIDOf(x.ProjectID))
+
+			phase := "Unknown"
+			if x.PodStatus != nil && x.PodStatus.Phase != "" {
+				phase = x.PodStatus.Phase
+			}
+
+			var restarts int
+			for _, c := range x.Containers {
+				restarts += c.RestartCount
+			}
+
+			e.setPodMetrics(x.Name, workloadNameOf(x.WorkloadID), x.NamespaceID, clusterName, phase, restarts)
+		} else if endpoint == "apps" {
+			var projectName = retrieveProjectRef(x.ProjectID)
+
+			if hideSys && projectName == systemProject {
+				continue
+			}
+
+			var clusterName = retrieveClusterRef(clusterIDOf(x.ProjectID))
+
+			catalog, template, version := parseAppExternalID(x.ExternalID)
+
+			var latest string
+			if t := e.catalog.findTemplate(e, catalog, template); t != nil {
+				latest = t.latestVersion()
+			}
+
+			e.setAppMetrics(x.Name, x.TargetNamespace, projectName, clusterName, x.State, catalog, template, version, latest)
+		} else if endpoint == "multiclusterapps" {
+			var catalog, template, version, latest string
+			if t, v := e.catalog.findTemplateVersion(e, x.TemplateVersionID); t != nil {
+				catalog, template, version, latest = t.catalogName(), t.FolderName, v, t.latestVersion()
+			}
+
+			e.setMultiClusterAppMetrics(x.Name, x.State, catalog, template, version, latest)
+		} else if endpoint == "etcdbackups" {
+			var clusterName = retrieveClusterRef(x.ClusterID)
+
+			summary, ok := etcdBackups[clusterName]
+			if !ok {
+				summary = new(etcdBackupSummary)
+				etcdBackups[clusterName] = summary
+			}
+			summary.total++
+
+			// Backups in progress are neither successful nor failed yet
+			var completed string
+			for _, c := range x.Conditions {
+				if c.Type == "Completed" {
+					completed = c.Status
+				}
+			}
+			switch {
+			case completed == "False" || x.State == "failed":
+				summary.failed++
+			case completed == "True" || (completed == "" && x.State == "active"):
+				if created, ok := parseTimestamp(x.Created); ok && created > summary.lastSuccess {
+					summary.lastSuccess = created
+				}
+			}
+		} else if endpoint == "clusterscans" {
+			// Scans still running have no results yet
+			if x.CisScanStatus == nil {
+				continue
+			}
+
+			var clusterName = retrieveClusterRef(x.ClusterID)
+
+			created, _ := parseTimestamp(x.Created)
+			if latest, ok := clusterScans[clusterName]; ok && latest.created >= created {
+				continue
+			}
+
+			scan := &clusterScan{created: created, status: x.CisScanStatus}
+			if x.ScanConfig != nil && x.ScanConfig.CisScanConfig != nil {
+				scan.profile = x.ScanConfig.CisScanConfig.Profile
+			}
+			clusterScans[clusterName] = scan
+		} else if endpoint == "tokens" {
+			security.tokens++
+			if x.Expired {
+				security.expiredTokens++
+			}
+			if x.ExpiresAt == "" {
+				security.nonExpiringTokens++
+			}
+
+			// The token the exporter authenticates with is flagged as current, its name is also the access key
+			if x.Current || x.Name == e.accessKey {
+				security.ownTokenExpiry, security.ownTokenExpires = parseTimestamp(x.ExpiresAt)
+			}
+		} else if endpoint == "users" {
+			security.users++
+			if x.Enabled == nil || *x.Enabled {
+				security.enabledUsers++
+			}
+		} else if endpoint == "gitrepos" {
+			// Fleet workspaces are the namespaces GitRepos are created in
+			e.setGitRepoMetrics(x.Name, x.NamespaceID, x.Repo, x.Branch, x.Commit, x.ReadyClusters, x.DesiredReadyClusters)
+		} else if endpoint == "bundles" {
+			if x.Summary != nil {
+				e.setBundleMetrics(x.Name, x.NamespaceID, x.Summary)
+			}
+		} else if endpoint == "backups" {
+			e.setBackupMetrics(x.Name, x.LastSnapshotTS, x.RetentionCount, x.Transitioning == "error")
+		} else if endpoint == "restores" {
+			e.setRestoreMetrics(x.Name, x.RestoreCompletionTS, x.Transitioning == "error")
+		} else if endpoint == "globalrolebindings" {
+			if x.GlobalRoleID == globalAdminRole {
+				if security.globalAdmins == nil {
+					security.globalAdmins = make(map[string]bool)
+				}
+				security.globalAdmins[x.UserID] = true
+			}
+		}
+	}
+
+	switch endpoint {
+	case "etcdbackups":
+		// Clusters without any backup report none, so a cluster that was never backed up can be alerted on
+		for _, clusterName := range clusterRef {
+			if _, ok := etcdBackups[clusterName]; !ok {
+				etcdBackups[clusterName] = new(etcdBackupSummary)
+			}
 		}
+		for clusterName, summary := range etcdBackups {
+			e.setEtcdBackupMetrics(clusterName, summary)
+		}
+	case "clusters":
+		if e.clusterProbe.enabled {
+			for clusterName, result := range e.probeClusters(clusters) {
+				e.setClusterProbeMetrics(clusterName, result)
+			}
+		}
+	case "clusterscans":
+		for clusterName, scan := range clusterScans {
+			e.setClusterScanMetrics(clusterName, scan)
+		}
+	case "tokens":
+		e.setTokenMetrics(&security)
+	case "users":
+		e.setUserMetrics(&security)
+	case "globalrolebindings":
+		e.setGlobalAdminMetrics(&security)
 	}
 
 	return nil
@@ -140,23 +693,107 @@ func (e *Exporter) processMetrics(data *Data, endpoint string, hideSys bool, ch
 
 // gatherData - Collects the data from thw API, invokes functions to transform that data into metrics
 func (e *Exporter) gatherData(rancherURL string, resourceLimit string, accessKey string, secretKey string, endpoint string, ch chan<- prometheus.Metric) (*Data, error) {
-	// Return the correct URL path
-	url := setEndpoint(rancherURL, endpoint, resourceLimit)
+	// Counted endpoints are listed in full, a truncated list would be counted as if it were complete
+	if countedEndpoints[endpoint] {
+		resourceLimit = countedLimit
+	}
+
+	// The Steve API has a different shape, its objects are converted as they are fetched
+	if steveEndpoint(endpoint, e.apiVersion, e.steve) {
+		return e.gatherSteveData(rancherURL, resourceLimit, accessKey, secretKey, endpoint)
+	}
+
+	// Cluster scoped endpoints are fetched once for every cluster discovered
+	if clusterScoped[endpoint] {
+		return e.gatherScopedData(rancherURL, resourceLimit, accessKey, secretKey, "cluster", clusterRef, clusterLinks, endpoint)
+	}
+
+	// Project scoped endpoints are fetched once for every project discovered
+	if projectScoped[endpoint] {
+		return e.gatherScopedData(rancherURL, resourceLimit, accessKey, secretKey, "project", projectRef, projectLinks, endpoint)
+	}
+
+	// Follow the link to the collection from the API root
+	url := e.collectionURL(endpoint, resourceLimit)
 
 	// Create new data slice from Struct
-	var data = new(Data)
+	var raw = new(RawData)
 
 	// Scrape EndPoint for JSON Data
-	err := getJSON(url, accessKey, secretKey, &data)
+	err := getJSON(url, accessKey, secretKey, &raw)
 	if err != nil {
 		log.Error("Error getting JSON from endpoint ", endpoint)
 		return nil, err
 	}
+
+	data := decodeResources(endpoint, raw.Data)
 	log.Debugf("JSON Fetched for: "+endpoint+": %+v", data)
 
 	return data, err
 }
 
+// gatherScopedData - Collects the data of an endpoint nested under each cluster or project, skipping any that fail
+func (e *Exporter) gatherScopedData(rancherURL string, resourceLimit string, accessKey string, secretKey string, scope string, refs map[string]string, refLinks map[string]map[string]string, endpoint string) (*Data, error) {
+	var data = new(Data)
+
+	for id := range refs {
+		url := setEndpoint(rancherURL, scope+"/"+id+"/"+endpoint, resourceLimit)
+		if links, ok := refLinks[id]; ok {
+			link, ok := links[endpoint]
+			if !ok {
+				log.Debugf("No %s collection linked from %s %s", endpoint, scope, id)
+				continue
+			}
+			url = withLimit(link, resourceLimit)
+		}
+
+		var scoped = new(RawData)
+		if err := getJSON(url, accessKey, secretKey, &scoped); err != nil {
+			log.Warnf("Error getting JSON from endpoint %s for %s %s", endpoint, scope, id)
+			continue
+		}
+		data.Data = append(data.Data, decodeResources(endpoint, scoped.Data).Data...)
+	}
+	log.Debugf("JSON Fetched for: "+endpoint+": %+v", data)
+
+	return data, nil
+}
+
+// decodeResources - Decodes the items of an endpoint, skipping any that fail. Fields that other kinds share a name with but not a type are decoded per kind.
+func decodeResources(endpoint string, items []json.RawMessage) *Data {
+	var data = new(Data)
+
+	for _, item := range items {
+		var x Resource
+		if err := json.Unmarshal(item, &x); err != nil {
+			log.Warnf("Error decoding %s: %s", endpoint, err)
+			continue
+		}
+
+		if endpoint == "clusters" {
+			var cluster clusterDetails
+			if err := json.Unmarshal(item, &cluster); err != nil {
+				log.Warnf("Error decoding cluster %s: %s", x.ID, err)
+				continue
+			}
+			x.Version = cluster.Version
+		}
+
+		if endpoint == "pods" {
+			var pod podDetails
+			if err := json.Unmarshal(item, &pod); err != nil {
+				log.Warnf("Error decoding pod %s: %s", x.ID, err)
+				continue
+			}
+			x.PodStatus = pod.Status
+		}
+
+		data.Data = append(data.Data, x)
+	}
+
+	return data
+}
+
 func (e *Exporter) allowedLabels(labels map[string]string) map[string]string {
 	result := make(map[string]string)
 	for name, val := range labels {
@@ -167,6 +804,48 @@ func (e *Exporter) allowedLabels(labels map[string]string) map[string]string {
 	return result
 }
 
+// allowedMountPoint - Checks a host mount point against the include and exclude filters for mount points and filesystem types
+func (e *Exporter) allowedMountPoint(device string) bool {
+	fsType := mountPointFSType(device)
+	f := e.mountFilter
+
+	if f.mountPointInclude != nil && !f.mountPointInclude.MatchString(device) {
+		return false
+	}
+	if f.mountPointExclude != nil && f.mountPointExclude.MatchString(device) {
+		return false
+	}
+	if f.fsTypeInclude != nil && !f.fsTypeInclude.MatchString(fsType) {
+		return false
+	}
+	if f.fsTypeExclude != nil && f.fsTypeExclude.MatchString(fsType) {
+		return false
+	}
+	return true
+}
+
+// allowedNamespace - Checks a namespace against the optional include and exclude filters for namespaces
+func (e *Exporter) allowedNamespace(namespace string) bool {
+	f := e.namespaceFilter
+
+	if f.include != nil && !f.include.MatchString(namespace) {
+		return false
+	}
+	if f.exclude != nil && f.exclude.MatchString(namespace) {
+		return false
+	}
+	return true
+}
+
+// mountPointFSType - The agent keys mount points by device and does not report a filesystem type.
+// Pseudo filesystems (tmpfs, overlay, shm) use their type as the device name, block devices return an empty type.
+func mountPointFSType(device string) string {
+	if strings.HasPrefix(device, "/") {
+		return ""
+	}
+	return device
+}
+
 // getJSON return json from server, return the formatted JSON
 func getJSON(url string, accessKey string, secretKey string, target interface{}) error {
 	start := time.Now()
@@ -181,6 +860,7 @@ func getJSON(url string, accessKey string, secretKey string, target interface{})
 
 	if err != nil {
 		log.Error("Error Collecting JSON from API: ", err)
+		return err
 	}
 
 	req.SetBasicAuth(accessKey, secretKey)
@@ -188,10 +868,13 @@ func getJSON(url string, accessKey string, secretKey string, target interface{})
 
 	if err != nil {
 		log.Error("Error Collecting JSON from API: ", err)
+		return err
 	}
 
 	if resp.StatusCode != 200 {
 		log.Error("Error Collecting JSON from API: ", resp.Status)
+		resp.Body.Close()
+		return fmt.Errorf("unexpected status from %s: %s", url, resp.Status)
 	}
 
 	respFormatted := json.NewDecoder(resp.Body).Decode(target)
@@ -209,12 +892,61 @@ func getJSON(url string, accessKey string, secretKey string, target interface{})
 
 // setEndpoint - Determines the correct URL endpoint to use, gives us backwards compatibility
 func setEndpoint(rancherURL string, component string, resourceLimit string) string {
-	var endpoint string
+	base := strings.TrimSuffix(rancherURL, "/")
+
+	// The v1 API of Rancher 1.x serves the same collections as v2-beta under older names, only the version in the path is swapped
+	if strings.HasSuffix(base, "/v1") {
+		base = strings.TrimSuffix(base, "v1") + "v2-beta"
+	}
 
-	endpoint = (rancherURL + "/" + component + "/" + "?limit=" + resourceLimit)
-	endpoint = strings.Replace(endpoint, "v1", "v2-beta", 1)
+	return base + "/" + component + "/" + "?limit=" + resourceLimit
+}
 
-	return endpoint
+// serverRoot - Strips the API version from the Rancher URL, leaving the address of the server itself
+func serverRoot(rancherURL string) string {
+	root := strings.TrimSuffix(rancherURL, "/")
+	if i := strings.LastIndex(root, "/"); i >= 0 && apiVersionPath.MatchString(root[i+1:]) {
+		root = root[:i]
+	}
+	return root
+}
+
+// gatherServerStatus - Pings the Rancher server and reads its version setting, the version is empty if it could not be read
+func (e *Exporter) gatherServerStatus(v3 bool) (healthy bool, duration float64, version string) {
+	start := time.Now()
+
+	client := &http.Client{Timeout: pingTimeout}
+	resp, err := client.Get(serverRoot(e.rancherURL) + "/ping")
+	duration = time.Since(start).Seconds()
+
+	if err != nil {
+		log.Error("Error pinging Rancher server: ", err)
+	} else {
+		healthy = resp.StatusCode == http.StatusOK
+		resp.Body.Close()
+		if !healthy {
+			log.Error("Error pinging Rancher server: ", resp.Status)
+		}
+	}
+
+	setting := serverVersionSetting
+	if v3 {
+		setting = serverVersionSettingV3
+	}
+
+	var data struct {
+		Value string `json:"value"`
+	}
+	url := strings.TrimSuffix(e.rancherURL, "/") + "/settings/" + setting
+	if e.apiVersion == apiSteve {
+		// Settings keep their value at the top level of the object, outside of spec and status
+		url = serverRoot(e.rancherURL) + "/v1/management.cattle.io.settings/" + serverVersionSettingV3
+	}
+	if err := getJSON(url, e.accessKey, e.secretKey, &data); err != nil {
+		log.Warn("Failed to read the Rancher server version: ", err)
+	}
+
+	return healthy, duration, data.Value
 }
 
 // storeStackRef stores the stackID and stack name for use as a label elsewhere
@@ -258,3 +990,44 @@ func retrieveClusterRef(clusterID string) string {
 	// returns unknown if no match was found
 	return "unknown"
 }
+
+// storeProjectRef stores the projectID and project name for use as a label elsewhere
+func storeProjectRef(projectID string, projectName string) map[string]string {
+	projectRef[projectID] = projectName
+
+	return projectRef
+}
+
+// retrieveProjectRef returns the project name, when sending the projectID
+func retrieveProjectRef(projectID string) string {
+	if name, ok := projectRef[projectID]; ok && projectID != "" {
+		return name
+	}
+	// returns unknown if no match was found
+	return "unknown"
+}
+
+// storeResourceLinks stores the links of a cluster or project, keyed by lower cased name, for fetching the collections nested under it
+func storeResourceLinks(refLinks map[string]map[string]string, id string, links map[string]string) map[string]map[string]string {
+	if len(links) == 0 {
+		return refLinks
+	}
+	lowered := make(map[string]string)
+	for name, link := range links {
+		lowered[strings.ToLower(name)] = link
+	}
+	refLinks[id] = lowered
+
+	return refLinks
+}
+
+// clusterIDOf returns the cluster part of a project ID, which takes the form `<clusterID>:<projectID>`
+func clusterIDOf(projectID string) string {
+	return strings.SplitN(projectID, ":", 2)[0]
+}
+
+// workloadNameOf returns the name part of a workload ID, which takes the form `<type>:<namespace>:<name>`
+func workloadNameOf(workloadID string) string {
+	parts := strings.Split(workloadID, ":")
+	return parts[len(parts)-1]
+}
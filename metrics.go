@@ -3,7 +3,9 @@ package main
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -22,10 +24,63 @@ func joinLabels(labels map[string]string) string {
 	return result
 }
 
+// parseTimestamp - Converts an RFC3339 timestamp from the Rancher API into Unix seconds, reports false if it is empty or invalid
+func parseTimestamp(timestamp string) (float64, bool) {
+	if timestamp == "" {
+		return 0, false
+	}
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		log.Debugf("Failed to parse timestamp %s: %s", timestamp, err)
+		return 0, false
+	}
+	return float64(t.Unix()), true
+}
+
 // addMetrics - Add's all of the GuageVecs to the `guageVecs` map, returns the map.
 func addMetrics() map[string]*prometheus.GaugeVec {
 	gaugeVecs := make(map[string]*prometheus.GaugeVec)
 
+	// Server Metrics
+	gaugeVecs["serverInfo"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "server_info",
+			Help:      "Version of the Rancher server, value is always 1",
+		}, []string{"version"})
+	gaugeVecs["apiInfo"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "api_info",
+			Help:      "Version of the Rancher API detected behind CATTLE_URL, value is always 1",
+		}, []string{"version"})
+	gaugeVecs["collectorEnabled"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "collector_enabled",
+			Help:      "Whether the collection read by defined collector is served by the Rancher API, either (1) or (0)",
+		}, []string{"collector"})
+	gaugeVecs["serverHealthy"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "server_healthy",
+			Help:      "Whether the Rancher server answered its ping, either (1) or (0)",
+		}, []string{})
+	gaugeVecs["serverPingDuration"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "server_ping_duration_seconds",
+			Help:      "Time taken by the Rancher server to answer its ping",
+		}, []string{})
+
+	// Certificate Metrics, labelled by cluster and component for v3 or by environment ID, name and cn for v2-beta
+	gaugeVecs["certificateExpiry"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "certificate_expiry_timestamp_seconds",
+			Help:      "Unix timestamp at which defined certificate expires",
+		}, []string{"cluster", "component", "environment_id", "name", "cn"})
+
 	// Stack Metrics
 	gaugeVecs["stacksHealth"] = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -39,6 +94,18 @@ func addMetrics() map[string]*prometheus.GaugeVec {
 			Name:      "stack_state",
 			Help:      "State of defined stack as reported by Rancher",
 		}, []string{"name", "state", "system"})
+	gaugeVecs["stackCatalogInfo"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "stack_catalog_info",
+			Help:      "Catalog template and version defined stack was launched from, value is always 1",
+		}, []string{"name", "catalog", "template", "version"})
+	gaugeVecs["stackUpgradeAvailable"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "stack_upgrade_available",
+			Help:      "Whether the catalog offers a newer version of the template defined stack was launched from, either (1) or (0)",
+		}, []string{"name"})
 
 	// Service Metrics
 	gaugeVecs["servicesScale"] = prometheus.NewGaugeVec(
@@ -79,29 +146,96 @@ func addMetrics() map[string]*prometheus.GaugeVec {
 			Name:      "host_cpu_count",
 			Help:      "Number of CPU Cores on host",
 		}, []string{"name", "labels"})
+	gaugeVecs["hostLoad1"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "host_load1",
+			Help:      "1 minute load average of the host",
+		}, []string{"name", "labels"})
+	gaugeVecs["hostLoad5"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "host_load5",
+			Help:      "5 minute load average of the host",
+		}, []string{"name", "labels"})
+	gaugeVecs["hostLoad15"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "host_load15",
+			Help:      "15 minute load average of the host",
+		}, []string{"name", "labels"})
+	gaugeVecs["hostCPUCoreUtilisation"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "host_cpu_core_utilisation",
+			Help:      "Utilisation of each CPU core on the host in percent",
+		}, []string{"name", "labels", "core"})
+	gaugeVecs["hostInfo"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "host_info",
+			Help:      "Software versions running on the host, value is always 1",
+		}, []string{"name", "labels", "docker_version", "kernel_version", "os", "agent_version"})
 	gaugeVecs["hostMemTotal"] = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: namespace,
 			Name:      "host_mem_total",
-			Help:      "Total memory size in MB",
+			Help:      "Total memory size in MB, deprecated in favour of host_mem_total_bytes",
 		}, []string{"name", "labels"})
 	gaugeVecs["hostMemFree"] = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: namespace,
 			Name:      "host_mem_free",
-			Help:      "Free memory size in MB",
+			Help:      "Free memory size in MB, deprecated in favour of host_mem_free_bytes",
 		}, []string{"name", "labels"})
 	gaugeVecs["hostMountPointTotal"] = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: namespace,
 			Name:      "host_mountpoint_total",
-			Help:      "Total size by mountpoint in MB",
+			Help:      "Total size by mountpoint in MB, deprecated in favour of host_mountpoint_total_bytes",
 		}, []string{"name", "labels", "mountpoint"})
 	gaugeVecs["hostMountPointUsed"] = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: namespace,
 			Name:      "host_mountpoint_used",
-			Help:      "Used size by mountpoint in MB",
+			Help:      "Used size by mountpoint in MB, deprecated in favour of host_mountpoint_used_bytes",
+		}, []string{"name", "labels", "mountpoint"})
+
+	gaugeVecs["hostMemTotalBytes"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "host_mem_total_bytes",
+			Help:      "Total memory size in bytes",
+		}, []string{"name", "labels"})
+	gaugeVecs["hostMemFreeBytes"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "host_mem_free_bytes",
+			Help:      "Free memory size in bytes",
+		}, []string{"name", "labels"})
+	gaugeVecs["hostMemUsageRatio"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "host_mem_usage_ratio",
+			Help:      "Ratio of used to total memory on the host, between 0 and 1",
+		}, []string{"name", "labels"})
+	gaugeVecs["hostMountPointTotalBytes"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "host_mountpoint_total_bytes",
+			Help:      "Total size by mountpoint in bytes",
+		}, []string{"name", "labels", "mountpoint"})
+	gaugeVecs["hostMountPointUsedBytes"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "host_mountpoint_used_bytes",
+			Help:      "Used size by mountpoint in bytes",
+		}, []string{"name", "labels", "mountpoint"})
+	gaugeVecs["hostMountPointUsageRatio"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "host_mountpoint_usage_ratio",
+			Help:      "Ratio of used to total size by mountpoint, between 0 and 1",
 		}, []string{"name", "labels", "mountpoint"})
 
 	// Cluster Metrics
@@ -117,6 +251,186 @@ func addMetrics() map[string]*prometheus.GaugeVec {
 			Name:      "cluster_component_status",
 			Help:      "State of components in defined cluster as reported by the Rancher API",
 		}, []string{"cluster_name", "status", "component_name"})
+	gaugeVecs["clusterCondition"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cluster_condition",
+			Help:      "Status of each condition of defined cluster as reported by the Rancher API",
+		}, []string{"cluster_name", "condition", "status"})
+	gaugeVecs["clusterConditionTransition"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cluster_condition_last_transition_timestamp_seconds",
+			Help:      "Unix timestamp of the last status change of each condition of defined cluster",
+		}, []string{"cluster_name", "condition"})
+	gaugeVecs["clusterInfo"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cluster_info",
+			Help:      "Kubernetes version and provisioning details of defined cluster, value is always 1",
+		}, []string{"cluster_name", "kubernetes_version", "provider", "driver", "agent_image"})
+	gaugeVecs["clusterEtcdBackupInterval"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cluster_etcd_backup_interval_seconds",
+			Help:      "Configured time between recurring etcd snapshots of defined RKE cluster",
+		}, []string{"cluster_name"})
+	gaugeVecs["etcdBackupLastSuccess"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "etcd_backup_last_success_timestamp_seconds",
+			Help:      "Unix timestamp of the latest successful etcd backup of defined cluster",
+		}, []string{"cluster_name"})
+	gaugeVecs["etcdBackups"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "etcd_backups",
+			Help:      "Number of etcd backups recorded by Rancher for defined cluster",
+		}, []string{"cluster_name"})
+	gaugeVecs["etcdBackupsFailed"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "etcd_backups_failed",
+			Help:      "Number of failed etcd backups recorded by Rancher for defined cluster",
+		}, []string{"cluster_name"})
+	gaugeVecs["cisScanChecks"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cis_scan_checks",
+			Help:      "Number of checks by result in the latest CIS scan of defined cluster",
+		}, []string{"cluster_name", "result"})
+	gaugeVecs["cisScanInfo"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cis_scan_info",
+			Help:      "Profile used by the latest CIS scan of defined cluster, value is always 1",
+		}, []string{"cluster_name", "profile"})
+	gaugeVecs["cisScanLastRun"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cis_scan_last_run_timestamp_seconds",
+			Help:      "Unix timestamp of the latest CIS scan of defined cluster",
+		}, []string{"cluster_name"})
+	gaugeVecs["clusterAPIUp"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cluster_api_up",
+			Help:      "Whether the Kubernetes API of defined cluster answered through the Rancher proxy, 1 = yes, 0 = no",
+		}, []string{"cluster_name"})
+	gaugeVecs["clusterAPIProbeDuration"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cluster_api_probe_duration_seconds",
+			Help:      "Time taken to probe the Kubernetes API of defined cluster through the Rancher proxy",
+		}, []string{"cluster_name"})
+	gaugeVecs["clusterAPIVersion"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cluster_api_version_info",
+			Help:      "Kubernetes version reported by the API of defined cluster through the Rancher proxy, value is always 1",
+		}, []string{"cluster_name", "version"})
+	gaugeVecs["fleetGitRepoReadyClusters"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "fleet_gitrepo_ready_clusters",
+			Help:      "Number of clusters on which every bundle of defined Fleet GitRepo is ready",
+		}, []string{"workspace", "gitrepo"})
+	gaugeVecs["fleetGitRepoDesiredReadyClusters"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "fleet_gitrepo_desired_ready_clusters",
+			Help:      "Number of clusters targeted by defined Fleet GitRepo",
+		}, []string{"workspace", "gitrepo"})
+	gaugeVecs["fleetGitRepoInfo"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "fleet_gitrepo_info",
+			Help:      "Repository, branch and last applied commit of defined Fleet GitRepo, value is always 1",
+		}, []string{"workspace", "gitrepo", "repo", "branch", "commit"})
+	gaugeVecs["fleetBundleDeployments"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "fleet_bundle_deployments",
+			Help:      "Number of deployments of defined Fleet bundle by state",
+		}, []string{"workspace", "bundle", "state"})
+	gaugeVecs["fleetBundleDesiredReady"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "fleet_bundle_desired_ready_deployments",
+			Help:      "Number of deployments of defined Fleet bundle expected to be ready",
+		}, []string{"workspace", "bundle"})
+	gaugeVecs["backupLastSuccess"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "backup_last_success_timestamp_seconds",
+			Help:      "Unix timestamp of the last successful snapshot taken by defined rancher-backup Backup",
+		}, []string{"backup_name"})
+	gaugeVecs["backupRetentionLimit"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "backup_retention_limit",
+			Help:      "Maximum number of snapshots defined recurring rancher-backup Backup is configured to keep",
+		}, []string{"backup_name"})
+	gaugeVecs["backupFailed"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "backup_failed",
+			Help:      "Whether Rancher reports defined rancher-backup Backup as failed, either (1) or (0)",
+		}, []string{"backup_name"})
+	gaugeVecs["restoreCompletion"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "restore_completion_timestamp_seconds",
+			Help:      "Unix timestamp at which defined rancher-backup Restore completed",
+		}, []string{"restore_name"})
+	gaugeVecs["restoreFailed"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "restore_failed",
+			Help:      "Whether Rancher reports defined rancher-backup Restore as failed, either (1) or (0)",
+		}, []string{"restore_name"})
+	gaugeVecs["clusterCapacity"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cluster_capacity",
+			Help:      "Total resources of defined cluster in base units (cores, bytes, pods)",
+		}, []string{"cluster_name", "resource"})
+	gaugeVecs["clusterAllocatable"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cluster_allocatable",
+			Help:      "Resources of defined cluster available for scheduling in base units (cores, bytes, pods)",
+		}, []string{"cluster_name", "resource"})
+	gaugeVecs["clusterRequested"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cluster_requested",
+			Help:      "Resources requested by pods in defined cluster in base units (cores, bytes, pods)",
+		}, []string{"cluster_name", "resource"})
+	gaugeVecs["clusterLimits"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cluster_limits",
+			Help:      "Resource limits of pods in defined cluster in base units (cores, bytes, pods)",
+		}, []string{"cluster_name", "resource"})
+	gaugeVecs["clusterRequestedRatio"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cluster_requested_ratio",
+			Help:      "Ratio of requested to allocatable resources in defined cluster",
+		}, []string{"cluster_name", "resource"})
+	gaugeVecs["clusterLimitsRatio"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cluster_limits_ratio",
+			Help:      "Ratio of pod resource limits to allocatable resources in defined cluster, above 1 when overcommitted",
+		}, []string{"cluster_name", "resource"})
+	gaugeVecs["clusterAllocatableRatio"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cluster_allocatable_ratio",
+			Help:      "Ratio of allocatable to total resources in defined cluster, the remainder is reserved for the system",
+		}, []string{"cluster_name", "resource"})
 
 	// Node Metrics
 	gaugeVecs["nodeState"] = prometheus.NewGaugeVec(
@@ -125,10 +439,251 @@ func addMetrics() map[string]*prometheus.GaugeVec {
 			Name:      "node_state",
 			Help:      "State of defined node as reported by the Rancher API",
 		}, []string{"cluster_name", "state", "node_name"})
+	gaugeVecs["nodeInfo"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "node_info",
+			Help:      "Software versions running on defined node, value is always 1",
+		}, []string{"cluster_name", "node_name", "kubelet_version", "container_runtime", "os_image", "kernel"})
+	gaugeVecs["nodeKubeletVersionMismatch"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "node_kubelet_version_mismatch",
+			Help:      "Whether the kubelet version of defined node differs from the Kubernetes version of its cluster, either (1) or (0)",
+		}, []string{"cluster_name", "node_name"})
+	gaugeVecs["nodeCapacity"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "node_capacity",
+			Help:      "Total resources of defined node in base units (cores, bytes, pods)",
+		}, []string{"cluster_name", "node_name", "resource"})
+	gaugeVecs["nodeAllocatable"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "node_allocatable",
+			Help:      "Resources of defined node available for scheduling in base units (cores, bytes, pods)",
+		}, []string{"cluster_name", "node_name", "resource"})
+	gaugeVecs["nodeRequested"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "node_requested",
+			Help:      "Resources requested by pods on defined node in base units (cores, bytes, pods)",
+		}, []string{"cluster_name", "node_name", "resource"})
+	gaugeVecs["nodeLimits"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "node_limits",
+			Help:      "Resource limits of pods on defined node in base units (cores, bytes, pods)",
+		}, []string{"cluster_name", "node_name", "resource"})
+	gaugeVecs["nodeCondition"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "node_condition",
+			Help:      "Status of each condition of defined node as reported by the Rancher API",
+		}, []string{"cluster_name", "node_name", "condition", "status"})
+	gaugeVecs["nodeRole"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "node_role",
+			Help:      "Roles assigned to defined node, either (1) or (0)",
+		}, []string{"cluster_name", "node_name", "role"})
+	gaugeVecs["nodeUnschedulable"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "node_unschedulable",
+			Help:      "Whether defined node is cordoned and unschedulable, either (1) or (0)",
+		}, []string{"cluster_name", "node_name"})
+	gaugeVecs["nodeTaint"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "node_taint",
+			Help:      "Taints applied to defined node, value is always 1",
+		}, []string{"cluster_name", "node_name", "key", "value", "effect"})
+
+	// Node Pool Metrics
+	gaugeVecs["nodePoolDesiredNodes"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "nodepool_desired_nodes",
+			Help:      "Number of nodes defined node pool is configured to provision",
+		}, []string{"cluster_name", "nodepool", "node_template", "driver"})
+	gaugeVecs["nodePoolNodes"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "nodepool_nodes",
+			Help:      "Number of nodes by state that exist in defined node pool",
+		}, []string{"cluster_name", "nodepool", "state"})
+
+	// Project Metrics
+	gaugeVecs["projectState"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "project_state",
+			Help:      "State of defined project as reported by the Rancher API",
+		}, []string{"cluster_name", "project_name", "state"})
+	gaugeVecs["projectNamespaces"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "project_namespaces",
+			Help:      "Number of namespaces assigned to defined project",
+		}, []string{"cluster_name", "project_name"})
+	gaugeVecs["projectQuotaLimit"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "project_resource_quota_limit",
+			Help:      "Resource quota limit of defined project in base units",
+		}, []string{"cluster_name", "project_name", "resource"})
+	gaugeVecs["projectQuotaUsed"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "project_resource_quota_used",
+			Help:      "Resource quota used by defined project in base units",
+		}, []string{"cluster_name", "project_name", "resource"})
+
+	// Workload Metrics
+	gaugeVecs["workloadState"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "workload_state",
+			Help:      "State of defined workload as reported by the Rancher API",
+		}, []string{"cluster_name", "project_name", "namespace", "workload_name", "workload_type", "state"})
+	gaugeVecs["workloadReplicasDesired"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "workload_replicas_desired",
+			Help:      "Number of replicas wanted for defined workload",
+		}, []string{"cluster_name", "project_name", "namespace", "workload_name", "workload_type"})
+	gaugeVecs["workloadReplicasReady"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "workload_replicas_ready",
+			Help:      "Number of ready replicas of defined workload",
+		}, []string{"cluster_name", "project_name", "namespace", "workload_name", "workload_type"})
+	gaugeVecs["workloadReplicasAvailable"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "workload_replicas_available",
+			Help:      "Number of available replicas of defined workload",
+		}, []string{"cluster_name", "project_name", "namespace", "workload_name", "workload_type"})
+	gaugeVecs["workloadReplicasUnavailable"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "workload_replicas_unavailable",
+			Help:      "Number of unavailable replicas of defined workload",
+		}, []string{"cluster_name", "project_name", "namespace", "workload_name", "workload_type"})
+
+	// App Metrics
+	gaugeVecs["appState"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "app_state",
+			Help:      "State of defined catalog app as reported by the Rancher API",
+		}, []string{"cluster_name", "project_name", "namespace", "app_name", "state"})
+	gaugeVecs["appInfo"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "app_info",
+			Help:      "Catalog template and version deployed by defined catalog app, value is always 1",
+		}, []string{"cluster_name", "project_name", "namespace", "app_name", "catalog", "template", "version"})
+	gaugeVecs["appUpgradeAvailable"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "app_upgrade_available",
+			Help:      "Whether the catalog offers a newer version than defined catalog app is running, either (1) or (0)",
+		}, []string{"cluster_name", "project_name", "namespace", "app_name"})
+	gaugeVecs["multiClusterAppState"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "multiclusterapp_state",
+			Help:      "State of defined multi-cluster app as reported by the Rancher API",
+		}, []string{"app_name", "state"})
+	gaugeVecs["multiClusterAppInfo"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "multiclusterapp_info",
+			Help:      "Catalog template and version deployed by defined multi-cluster app, value is always 1",
+		}, []string{"app_name", "catalog", "template", "version"})
+	gaugeVecs["multiClusterAppUpgradeAvailable"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "multiclusterapp_upgrade_available",
+			Help:      "Whether the catalog offers a newer version than defined multi-cluster app is running, either (1) or (0)",
+		}, []string{"app_name"})
+
+	// Security Metrics
+	gaugeVecs["ownTokenExpiry"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "exporter_token_expiry_timestamp_seconds",
+			Help:      "Unix timestamp at which the API token used by this exporter expires, absent if it never expires",
+		}, []string{})
+	gaugeVecs["tokens"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "tokens",
+			Help:      "Number of API tokens visible to the exporter",
+		}, []string{})
+	gaugeVecs["tokensExpired"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "tokens_expired",
+			Help:      "Number of expired API tokens",
+		}, []string{})
+	gaugeVecs["tokensNonExpiring"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "tokens_non_expiring",
+			Help:      "Number of API tokens that never expire",
+		}, []string{})
+	gaugeVecs["users"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "users",
+			Help:      "Number of Rancher users",
+		}, []string{})
+	gaugeVecs["usersEnabled"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "users_enabled",
+			Help:      "Number of enabled Rancher users",
+		}, []string{})
+	gaugeVecs["usersGlobalAdmins"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "users_global_admins",
+			Help:      "Number of Rancher users bound to the global admin role",
+		}, []string{})
+
+	// Pod Metrics
+	gaugeVecs["pods"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "pods",
+			Help:      "Number of pods by phase for defined workload",
+		}, []string{"cluster_name", "namespace", "workload_name", "phase"})
+	gaugeVecs["podContainerRestarts"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "pod_container_restarts",
+			Help:      "Total container restarts across the pods of defined workload",
+		}, []string{"cluster_name", "namespace", "workload_name"})
+	gaugeVecs["podPhase"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "pod_phase",
+			Help:      "Phase of defined pod, only exported when POD_METRICS_PER_POD is enabled",
+		}, []string{"cluster_name", "namespace", "workload_name", "pod_name", "phase"})
+	gaugeVecs["podRestarts"] = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "pod_restarts",
+			Help:      "Total container restarts of defined pod, only exported when POD_METRICS_PER_POD is enabled",
+		}, []string{"cluster_name", "namespace", "workload_name", "pod_name"})
 	return gaugeVecs
 }
 
 // checkMetric - Checks the base type stored in the API is correct, this ensures we are setting the right metric for the right endpoint.
+// Endpoints are lower case while types are camel case, e.g. `multiclusterapps` holds `multiClusterApp`.
 func checkMetric(endpoint string, baseType string) bool {
 	e := strings.TrimSuffix(endpoint, "s")
 
@@ -137,7 +692,7 @@ func checkMetric(endpoint string, baseType string) bool {
 		return true
 	} else if e == "service" && (baseType == "externalService" || baseType == "loadBalancerService") {
 		return true
-	} else if e != baseType {
+	} else if !strings.EqualFold(e, baseType) {
 		log.Errorf("API MisMatch, expected %s metric, got %s metric", e, baseType)
 		return false
 	}
@@ -145,6 +700,61 @@ func checkMetric(endpoint string, baseType string) bool {
 	return true
 }
 
+// setAPIMetrics - Logic to set the detected API version as a gauge metric, nothing is set until detection succeeds
+func (e *Exporter) setAPIMetrics(version string) {
+	if version != "" {
+		e.gaugeVecs["apiInfo"].With(prometheus.Labels{"version": version}).Set(1)
+	}
+}
+
+// setCollectorMetrics - Logic to set whether each collector is enabled as gauge metrics
+func (e *Exporter) setCollectorMetrics(collectors map[string]bool) {
+	for collector, enabled := range collectors {
+		if enabled {
+			e.gaugeVecs["collectorEnabled"].With(prometheus.Labels{"collector": collector}).Set(1)
+		} else {
+			e.gaugeVecs["collectorEnabled"].With(prometheus.Labels{"collector": collector}).Set(0)
+		}
+	}
+}
+
+// setServerMetrics - Logic to set the health and version of the Rancher server as gauge metrics
+func (e *Exporter) setServerMetrics(healthy bool, duration float64, version string) {
+	if healthy {
+		e.gaugeVecs["serverHealthy"].With(prometheus.Labels{}).Set(1)
+	} else {
+		e.gaugeVecs["serverHealthy"].With(prometheus.Labels{}).Set(0)
+	}
+	e.gaugeVecs["serverPingDuration"].With(prometheus.Labels{}).Set(duration)
+
+	if version != "" {
+		e.gaugeVecs["serverInfo"].With(prometheus.Labels{"version": version}).Set(1)
+	}
+}
+
+// setCertificateExpiryMetrics - Logic to set the expiry of a certificate as a gauge metric, labels not given are left empty
+func (e *Exporter) setCertificateExpiryMetrics(labels prometheus.Labels, expiresAt string) {
+	expiry, ok := parseTimestamp(expiresAt)
+	if !ok {
+		if expiresAt != "" {
+			log.Warnf("Failed to parse the expiry %q of certificate %v, it is not exported", expiresAt, labels)
+		}
+		return
+	}
+
+	certLabels := prometheus.Labels{
+		"cluster":        "",
+		"component":      "",
+		"environment_id": "",
+		"name":           "",
+		"cn":             "",
+	}
+	for k, v := range labels {
+		certLabels[k] = v
+	}
+	e.gaugeVecs["certificateExpiry"].With(certLabels).Set(expiry)
+}
+
 // setServiceMetrics - Logic to set the state of a system as a gauge metric
 func (e *Exporter) setServiceMetrics(name string, stack string, state string, health string, scale int, labels map[string]string) {
 	labelsStr := joinLabels(labels)
@@ -209,7 +819,31 @@ func (e *Exporter) setStackMetrics(name string, state string, health string, sys
 	}
 }
 
-func (e *Exporter) setHostInfoMetrics(name string, hi *HostInfo, labels map[string]string) {
+// setStackCatalogMetrics - Logic to set the catalog version of a stack and whether it can be upgraded as gauge metrics
+func (e *Exporter) setStackCatalogMetrics(name string, catalog string, template string, version string, upgradeAvailable bool, found bool) {
+	e.gaugeVecs["stackCatalogInfo"].With(prometheus.Labels{
+		"name":     name,
+		"catalog":  catalog,
+		"template": template,
+		"version":  version,
+	}).Set(1)
+
+	// Without the template from the catalog there is nothing to compare against
+	if !found {
+		return
+	}
+	gauge := e.gaugeVecs["stackUpgradeAvailable"].With(prometheus.Labels{
+		"name": name,
+	})
+	if upgradeAvailable {
+		gauge.Set(1)
+	} else {
+		gauge.Set(0)
+	}
+}
+
+// setHostInfoMetrics - Logic to set the resource usage and versions of a host as gauge metrics
+func (e *Exporter) setHostInfoMetrics(name string, hi *HostInfo, agentVersion string, labels map[string]string) {
 	labelsStr := joinLabels(labels)
 
 	e.gaugeVecs["hostCPUCount"].With(prometheus.Labels{
@@ -217,28 +851,90 @@ func (e *Exporter) setHostInfoMetrics(name string, hi *HostInfo, labels map[stri
 		"labels": labelsStr,
 	}).Set(float64(hi.CPUInfo.Count))
 
-	e.gaugeVecs["hostMemTotal"].With(prometheus.Labels{
+	// The agent reports the 1, 5 and 15 minute load averages in that order
+	loadAvgs := []string{"hostLoad1", "hostLoad5", "hostLoad15"}
+	for i, load := range hi.CPUInfo.LoadAvg {
+		if i >= len(loadAvgs) {
+			break
+		}
+		e.gaugeVecs[loadAvgs[i]].With(prometheus.Labels{
+			"name":   name,
+			"labels": labelsStr,
+		}).Set(load)
+	}
+
+	for core, percentage := range hi.CPUInfo.CPUCoresPercentages {
+		e.gaugeVecs["hostCPUCoreUtilisation"].With(prometheus.Labels{
+			"name":   name,
+			"labels": labelsStr,
+			"core":   strconv.Itoa(core),
+		}).Set(percentage)
+	}
+
+	e.gaugeVecs["hostInfo"].With(prometheus.Labels{
+		"name":           name,
+		"labels":         labelsStr,
+		"docker_version": hi.OSInfo.DockerVersion,
+		"kernel_version": hi.OSInfo.KernelVersion,
+		"os":             hi.OSInfo.OperatingSystem,
+		"agent_version":  agentVersion,
+	}).Set(1)
+
+	memTotal := hi.MemoryInfo.MemTotal * memoryUnit
+	memFree := hi.MemoryInfo.MemFree * memoryUnit
+
+	e.gaugeVecs["hostMemTotalBytes"].With(prometheus.Labels{
 		"name":   name,
 		"labels": labelsStr,
-	}).Set(float64(hi.MemoryInfo.MemTotal))
+	}).Set(memTotal)
 
-	e.gaugeVecs["hostMemFree"].With(prometheus.Labels{
+	e.gaugeVecs["hostMemFreeBytes"].With(prometheus.Labels{
 		"name":   name,
 		"labels": labelsStr,
-	}).Set(float64(hi.MemoryInfo.MemFree))
+	}).Set(memFree)
+
+	if memTotal > 0 {
+		e.gaugeVecs["hostMemUsageRatio"].With(prometheus.Labels{
+			"name":   name,
+			"labels": labelsStr,
+		}).Set((memTotal - memFree) / memTotal)
+	}
+
+	if e.legacyHostMB {
+		e.gaugeVecs["hostMemTotal"].With(prometheus.Labels{
+			"name":   name,
+			"labels": labelsStr,
+		}).Set(hi.MemoryInfo.MemTotal)
+
+		e.gaugeVecs["hostMemFree"].With(prometheus.Labels{
+			"name":   name,
+			"labels": labelsStr,
+		}).Set(hi.MemoryInfo.MemFree)
+	}
 
 	for mountName, mountPoint := range hi.DiskInfo.MountPoints {
-		e.gaugeVecs["hostMountPointTotal"].With(prometheus.Labels{
+		mountLabels := prometheus.Labels{
 			"name":       name,
 			"labels":     labelsStr,
 			"mountpoint": mountName,
-		}).Set(float64(mountPoint.Total))
+		}
 
-		e.gaugeVecs["hostMountPointUsed"].With(prometheus.Labels{
-			"name":       name,
-			"labels":     labelsStr,
-			"mountpoint": mountName,
-		}).Set(float64(mountPoint.Used))
+		// The legacy metrics keep every mount point they always exported, the filters only apply to the metrics in bytes
+		if e.legacyHostMB {
+			e.gaugeVecs["hostMountPointTotal"].With(mountLabels).Set(mountPoint.Total)
+			e.gaugeVecs["hostMountPointUsed"].With(mountLabels).Set(mountPoint.Used)
+		}
+
+		if !e.allowedMountPoint(mountName) {
+			continue
+		}
+
+		e.gaugeVecs["hostMountPointTotalBytes"].With(mountLabels).Set(mountPoint.Total * diskUnit)
+		e.gaugeVecs["hostMountPointUsedBytes"].With(mountLabels).Set(mountPoint.Used * diskUnit)
+
+		if mountPoint.Total > 0 {
+			e.gaugeVecs["hostMountPointUsageRatio"].With(mountLabels).Set(mountPoint.Used / mountPoint.Total)
+		}
 	}
 }
 
@@ -286,19 +982,223 @@ func (e *Exporter) setClusterMetrics(name string, state string, statuses []*Comp
 	}
 
 	for _, status := range statuses {
+		componentState := componentHealth(status.Conditions)
+		if len(status.Conditions) == 0 {
+			log.Debugf("No conditions reported for component %s in cluster %s, reporting it as Unknown", status.Name, name)
+		}
 		for _, y := range componentStatus {
 			gauge := e.gaugeVecs["clusterComponentStatus"].With(prometheus.Labels{
 				"cluster_name":   name,
 				"status":         y,
 				"component_name": status.Name,
 			})
-			if status.Conditions[0].Status == y {
+			if componentState == y {
+				gauge.Set(1)
+			} else {
+				gauge.Set(0)
+			}
+		}
+	}
+}
+
+// componentHealth - Returns the status of a component's `Healthy` condition, falling back to its first condition, or `Unknown` if it reports none
+func componentHealth(conditions []*Condition) string {
+	for _, condition := range conditions {
+		if condition.Type == "Healthy" {
+			return condition.Status
+		}
+	}
+	if len(conditions) > 0 {
+		return conditions[0].Status
+	}
+	return "Unknown"
+}
+
+// setClusterConditionMetrics - Logic to set the conditions of a cluster and when they last changed as gauge metrics
+func (e *Exporter) setClusterConditionMetrics(name string, conditions []*Condition) {
+	for _, condition := range conditions {
+		for _, y := range componentStatus {
+			gauge := e.gaugeVecs["clusterCondition"].With(prometheus.Labels{
+				"cluster_name": name,
+				"condition":    condition.Type,
+				"status":       y,
+			})
+			if condition.Status == y {
 				gauge.Set(1)
 			} else {
 				gauge.Set(0)
 			}
 		}
+
+		if ts, ok := parseTimestamp(condition.LastTransitionTime); ok {
+			e.gaugeVecs["clusterConditionTransition"].With(prometheus.Labels{
+				"cluster_name": name,
+				"condition":    condition.Type,
+			}).Set(ts)
+		}
+	}
+}
+
+// setClusterInfoMetrics - Logic to set the version details of a cluster as an info metric
+func (e *Exporter) setClusterInfoMetrics(name string, kubernetesVersion, provider, driver, agentImage string) {
+	e.gaugeVecs["clusterInfo"].With(prometheus.Labels{
+		"cluster_name":       name,
+		"kubernetes_version": kubernetesVersion,
+		"provider":           provider,
+		"driver":             driver,
+		"agent_image":        agentImage,
+	}).Set(1)
+}
+
+// setClusterEtcdBackupInterval - Logic to set the configured etcd snapshot interval of a cluster as a gauge metric
+func (e *Exporter) setClusterEtcdBackupInterval(name string, interval time.Duration) {
+	if interval == 0 {
+		return
+	}
+	e.gaugeVecs["clusterEtcdBackupInterval"].With(prometheus.Labels{
+		"cluster_name": name,
+	}).Set(interval.Seconds())
+}
+
+// setEtcdBackupMetrics - Logic to set the backup counts and latest successful backup of a cluster as gauge metrics
+func (e *Exporter) setEtcdBackupMetrics(clusterName string, summary *etcdBackupSummary) {
+	labels := prometheus.Labels{
+		"cluster_name": clusterName,
+	}
+	e.gaugeVecs["etcdBackups"].With(labels).Set(float64(summary.total))
+	e.gaugeVecs["etcdBackupsFailed"].With(labels).Set(float64(summary.failed))
+
+	if summary.lastSuccess > 0 {
+		e.gaugeVecs["etcdBackupLastSuccess"].With(labels).Set(summary.lastSuccess)
+	}
+}
+
+// setBackupMetrics - Logic to set the last snapshot, retention and failure of a rancher-backup Backup as gauge metrics
+func (e *Exporter) setBackupMetrics(name string, lastSnapshot string, retentionLimit int, failed bool) {
+	// Backups that have never succeeded have no snapshot time, alert on absent() for them
+	if t, ok := parseTimestamp(lastSnapshot); ok {
+		e.gaugeVecs["backupLastSuccess"].With(prometheus.Labels{"backup_name": name}).Set(t)
+	}
+
+	// One-time backups keep their single snapshot, only recurring backups set a retention limit.
+	// The operator doesn't report how many snapshots it currently holds, only how many it keeps at most.
+	if retentionLimit > 0 {
+		e.gaugeVecs["backupRetentionLimit"].With(prometheus.Labels{"backup_name": name}).Set(float64(retentionLimit))
+	}
+
+	if failed {
+		e.gaugeVecs["backupFailed"].With(prometheus.Labels{"backup_name": name}).Set(1)
+	} else {
+		e.gaugeVecs["backupFailed"].With(prometheus.Labels{"backup_name": name}).Set(0)
+	}
+}
+
+// setRestoreMetrics - Logic to set the completion and failure of a rancher-backup Restore as gauge metrics
+func (e *Exporter) setRestoreMetrics(name string, completion string, failed bool) {
+	if t, ok := parseTimestamp(completion); ok {
+		e.gaugeVecs["restoreCompletion"].With(prometheus.Labels{"restore_name": name}).Set(t)
+	}
+
+	if failed {
+		e.gaugeVecs["restoreFailed"].With(prometheus.Labels{"restore_name": name}).Set(1)
+	} else {
+		e.gaugeVecs["restoreFailed"].With(prometheus.Labels{"restore_name": name}).Set(0)
+	}
+}
+
+// setGitRepoMetrics - Logic to set the cluster readiness and source of a Fleet GitRepo as gauge metrics
+func (e *Exporter) setGitRepoMetrics(name string, workspace string, repo string, branch string, commit string, ready int, desiredReady int) {
+	e.gaugeVecs["fleetGitRepoReadyClusters"].With(prometheus.Labels{"workspace": workspace, "gitrepo": name}).Set(float64(ready))
+	e.gaugeVecs["fleetGitRepoDesiredReadyClusters"].With(prometheus.Labels{"workspace": workspace, "gitrepo": name}).Set(float64(desiredReady))
+	e.gaugeVecs["fleetGitRepoInfo"].With(prometheus.Labels{
+		"workspace": workspace,
+		"gitrepo":   name,
+		"repo":      repo,
+		"branch":    branch,
+		"commit":    commit,
+	}).Set(1)
+}
+
+// setBundleMetrics - Logic to set the deployment states of a Fleet bundle as gauge metrics
+func (e *Exporter) setBundleMetrics(name string, workspace string, summary *BundleSummary) {
+	// States as named by Fleet
+	states := map[string]int{
+		"Ready":       summary.Ready,
+		"NotReady":    summary.NotReady,
+		"WaitApplied": summary.WaitApplied,
+		"ErrApplied":  summary.ErrApplied,
+		"OutOfSync":   summary.OutOfSync,
+		"Modified":    summary.Modified,
+		"Pending":     summary.Pending,
+	}
+	for state, deployments := range states {
+		e.gaugeVecs["fleetBundleDeployments"].With(prometheus.Labels{
+			"workspace": workspace,
+			"bundle":    name,
+			"state":     state,
+		}).Set(float64(deployments))
+	}
+
+	e.gaugeVecs["fleetBundleDesiredReady"].With(prometheus.Labels{"workspace": workspace, "bundle": name}).Set(float64(summary.DesiredReady))
+}
+
+// setClusterProbeMetrics - Logic to set the outcome of a cluster API probe as gauge metrics
+func (e *Exporter) setClusterProbeMetrics(clusterName string, result clusterProbeResult) {
+	var up float64
+	if result.up {
+		up = 1
+	}
+	e.gaugeVecs["clusterAPIUp"].With(prometheus.Labels{"cluster_name": clusterName}).Set(up)
+	e.gaugeVecs["clusterAPIProbeDuration"].With(prometheus.Labels{"cluster_name": clusterName}).Set(result.duration)
+
+	if result.up {
+		e.gaugeVecs["clusterAPIVersion"].With(prometheus.Labels{
+			"cluster_name": clusterName,
+			"version":      result.version,
+		}).Set(1)
+	}
+}
+
+// setClusterScanMetrics - Logic to set the results of the latest CIS scan of a cluster as gauge metrics
+func (e *Exporter) setClusterScanMetrics(clusterName string, scan *clusterScan) {
+	results := map[string]int{
+		"pass":           scan.status.Pass,
+		"fail":           scan.status.Fail,
+		"skip":           scan.status.Skip,
+		"not_applicable": scan.status.NotApplicable,
+	}
+	for result, checks := range results {
+		e.gaugeVecs["cisScanChecks"].With(prometheus.Labels{
+			"cluster_name": clusterName,
+			"result":       result,
+		}).Set(float64(checks))
+	}
+
+	e.gaugeVecs["cisScanInfo"].With(prometheus.Labels{
+		"cluster_name": clusterName,
+		"profile":      scan.profile,
+	}).Set(1)
+
+	if scan.created > 0 {
+		e.gaugeVecs["cisScanLastRun"].With(prometheus.Labels{
+			"cluster_name": clusterName,
+		}).Set(scan.created)
+	}
+}
+
+// setClusterResourceMetrics - Logic to set the aggregated resource quantities of a cluster as gauge metrics
+func (e *Exporter) setClusterResourceMetrics(name string, capacity, allocatable, requested, limits map[string]string) {
+	labels := prometheus.Labels{
+		"cluster_name": name,
 	}
+	setResourceMetrics(e.gaugeVecs["clusterCapacity"], labels, capacity)
+	setResourceMetrics(e.gaugeVecs["clusterAllocatable"], labels, allocatable)
+	setResourceMetrics(e.gaugeVecs["clusterRequested"], labels, requested)
+	setResourceMetrics(e.gaugeVecs["clusterLimits"], labels, limits)
+
+	setResourceRatioMetrics(e.gaugeVecs["clusterRequestedRatio"], labels, requested, allocatable)
+	setResourceRatioMetrics(e.gaugeVecs["clusterLimitsRatio"], labels, limits, allocatable)
+	setResourceRatioMetrics(e.gaugeVecs["clusterAllocatableRatio"], labels, allocatable, capacity)
 }
 
 // setNodeMetrics - Logic to set the state of a system as a gauge metric
@@ -316,3 +1216,392 @@ func (e *Exporter) setNodeMetrics(nodeName string, state string, clusterName str
 		}
 	}
 }
+
+// setNodeResourceMetrics - Logic to set the resource quantities of a node as gauge metrics
+func (e *Exporter) setNodeResourceMetrics(nodeName string, clusterName string, capacity, allocatable, requested, limits map[string]string) {
+	labels := prometheus.Labels{
+		"cluster_name": clusterName,
+		"node_name":    nodeName,
+	}
+	setResourceMetrics(e.gaugeVecs["nodeCapacity"], labels, capacity)
+	setResourceMetrics(e.gaugeVecs["nodeAllocatable"], labels, allocatable)
+	setResourceMetrics(e.gaugeVecs["nodeRequested"], labels, requested)
+	setResourceMetrics(e.gaugeVecs["nodeLimits"], labels, limits)
+}
+
+// setResourceMetrics - Sets a gauge per known resource, converting the Kubernetes quantity into base units
+func setResourceMetrics(gaugeVec *prometheus.GaugeVec, labels prometheus.Labels, quantities map[string]string) {
+	for _, resource := range nodeResources {
+		quantity, ok := quantities[resource]
+		if !ok {
+			continue
+		}
+		value, err := parseQuantity(quantity)
+		if err != nil {
+			log.Warnf("Failed to parse %s quantity: %s", resource, err)
+			continue
+		}
+
+		resourceLabels := prometheus.Labels{"resource": resource}
+		for name, val := range labels {
+			resourceLabels[name] = val
+		}
+		gaugeVec.With(resourceLabels).Set(value)
+	}
+}
+
+// setResourceRatioMetrics - Sets the ratio of two resource quantities for each resource, skipping resources missing from either or with nothing to divide by
+func setResourceRatioMetrics(gaugeVec *prometheus.GaugeVec, labels prometheus.Labels, numerators, denominators map[string]string) {
+	for _, resource := range nodeResources {
+		if numerators[resource] == "" || denominators[resource] == "" {
+			continue
+		}
+		denominator, err := parseQuantity(denominators[resource])
+		if err != nil || denominator == 0 {
+			continue
+		}
+		numerator, err := parseQuantity(numerators[resource])
+		if err != nil {
+			continue
+		}
+
+		resourceLabels := prometheus.Labels{"resource": resource}
+		for name, val := range labels {
+			resourceLabels[name] = val
+		}
+		gaugeVec.With(resourceLabels).Set(numerator / denominator)
+	}
+}
+
+// setNodeStatusMetrics - Logic to set the conditions, roles and scheduling state of a node as gauge metrics
+func (e *Exporter) setNodeStatusMetrics(nodeName string, clusterName string, conditions []*Condition, roles map[string]bool, unschedulable bool, taints []*Taint) {
+	for _, condition := range conditions {
+		for _, y := range componentStatus {
+			gauge := e.gaugeVecs["nodeCondition"].With(prometheus.Labels{
+				"cluster_name": clusterName,
+				"node_name":    nodeName,
+				"condition":    condition.Type,
+				"status":       y,
+			})
+			if condition.Status == y {
+				gauge.Set(1)
+			} else {
+				gauge.Set(0)
+			}
+		}
+	}
+
+	for _, y := range nodeRoles {
+		gauge := e.gaugeVecs["nodeRole"].With(prometheus.Labels{
+			"cluster_name": clusterName,
+			"node_name":    nodeName,
+			"role":         y,
+		})
+		if roles[y] {
+			gauge.Set(1)
+		} else {
+			gauge.Set(0)
+		}
+	}
+
+	gauge := e.gaugeVecs["nodeUnschedulable"].With(prometheus.Labels{
+		"cluster_name": clusterName,
+		"node_name":    nodeName,
+	})
+	if unschedulable {
+		gauge.Set(1)
+	} else {
+		gauge.Set(0)
+	}
+
+	for _, taint := range taints {
+		e.gaugeVecs["nodeTaint"].With(prometheus.Labels{
+			"cluster_name": clusterName,
+			"node_name":    nodeName,
+			"key":          taint.Key,
+			"value":        taint.Value,
+			"effect":       taint.Effect,
+		}).Set(1)
+	}
+}
+
+// setNodeInfoMetrics - Logic to set the software versions of a node as an info metric, flagging kubelets that differ from their cluster
+func (e *Exporter) setNodeInfoMetrics(nodeName string, clusterName string, ni *HostInfo, clusterVersion string) {
+	e.gaugeVecs["nodeInfo"].With(prometheus.Labels{
+		"cluster_name":      clusterName,
+		"node_name":         nodeName,
+		"kubelet_version":   ni.Kubernetes.KubeletVersion,
+		"container_runtime": ni.OS.DockerVersion,
+		"os_image":          ni.OS.OperatingSystem,
+		"kernel":            ni.OS.KernelVersion,
+	}).Set(1)
+
+	// Without both versions there is nothing to compare
+	if clusterVersion == "" || ni.Kubernetes.KubeletVersion == "" {
+		return
+	}
+
+	gauge := e.gaugeVecs["nodeKubeletVersionMismatch"].With(prometheus.Labels{
+		"cluster_name": clusterName,
+		"node_name":    nodeName,
+	})
+	if ni.Kubernetes.KubeletVersion != clusterVersion {
+		gauge.Set(1)
+	} else {
+		gauge.Set(0)
+	}
+}
+
+// setProjectMetrics - Logic to set the state and resource quota of a project as gauge metrics
+func (e *Exporter) setProjectMetrics(name string, state string, clusterName string, quota *ResourceQuota) {
+	for _, y := range projectStates {
+		gauge := e.gaugeVecs["projectState"].With(prometheus.Labels{
+			"cluster_name": clusterName,
+			"project_name": name,
+			"state":        y,
+		})
+		if state == y {
+			gauge.Set(1)
+		} else {
+			gauge.Set(0)
+		}
+	}
+
+	// Namespaces are counted as they are processed, start from zero so empty projects are reported
+	e.gaugeVecs["projectNamespaces"].With(prometheus.Labels{
+		"cluster_name": clusterName,
+		"project_name": name,
+	}).Set(0)
+
+	if quota == nil {
+		return
+	}
+	setQuotaMetrics(e.gaugeVecs["projectQuotaLimit"], clusterName, name, quota.Limit)
+	setQuotaMetrics(e.gaugeVecs["projectQuotaUsed"], clusterName, name, quota.UsedLimit)
+}
+
+// setQuotaMetrics - Sets a gauge for every resource in a project quota, converting the Kubernetes quantity into base units
+func setQuotaMetrics(gaugeVec *prometheus.GaugeVec, clusterName string, projectName string, quantities map[string]string) {
+	for resource, quantity := range quantities {
+		value, err := parseQuantity(quantity)
+		if err != nil {
+			log.Warnf("Failed to parse %s quota: %s", resource, err)
+			continue
+		}
+		gaugeVec.With(prometheus.Labels{
+			"cluster_name": clusterName,
+			"project_name": projectName,
+			"resource":     resource,
+		}).Set(value)
+	}
+}
+
+// countProjectNamespace - Adds a namespace to the count of namespaces in its project
+func (e *Exporter) countProjectNamespace(projectName string, clusterName string) {
+	e.gaugeVecs["projectNamespaces"].With(prometheus.Labels{
+		"cluster_name": clusterName,
+		"project_name": projectName,
+	}).Inc()
+}
+
+// setWorkloadMetrics - Logic to set the state and replica counts of a workload as gauge metrics
+func (e *Exporter) setWorkloadMetrics(name string, workloadType string, namespaceName string, projectName string, clusterName string, state string, replicas *WorkloadReplicas) {
+	labels := prometheus.Labels{
+		"cluster_name":  clusterName,
+		"project_name":  projectName,
+		"namespace":     namespaceName,
+		"workload_name": name,
+		"workload_type": workloadType,
+	}
+
+	for _, y := range workloadStates {
+		stateLabels := prometheus.Labels{"state": y}
+		for k, v := range labels {
+			stateLabels[k] = v
+		}
+		gauge := e.gaugeVecs["workloadState"].With(stateLabels)
+		if state == y {
+			gauge.Set(1)
+		} else {
+			gauge.Set(0)
+		}
+	}
+
+	if replicas == nil {
+		return
+	}
+	e.gaugeVecs["workloadReplicasDesired"].With(labels).Set(float64(replicas.Desired))
+	e.gaugeVecs["workloadReplicasReady"].With(labels).Set(float64(replicas.Ready))
+	e.gaugeVecs["workloadReplicasAvailable"].With(labels).Set(float64(replicas.Available))
+	e.gaugeVecs["workloadReplicasUnavailable"].With(labels).Set(float64(replicas.Unavailable))
+}
+
+// setPodMetrics - Logic to add a pod to the counts of its workload, and to set per pod gauges when enabled
+func (e *Exporter) setPodMetrics(name string, workloadName string, namespaceName string, clusterName string, phase string, restarts int) {
+	e.gaugeVecs["pods"].With(prometheus.Labels{
+		"cluster_name":  clusterName,
+		"namespace":     namespaceName,
+		"workload_name": workloadName,
+		"phase":         phase,
+	}).Inc()
+
+	e.gaugeVecs["podContainerRestarts"].With(prometheus.Labels{
+		"cluster_name":  clusterName,
+		"namespace":     namespaceName,
+		"workload_name": workloadName,
+	}).Add(float64(restarts))
+
+	if !e.podsPerPod {
+		return
+	}
+
+	for _, y := range podPhases {
+		gauge := e.gaugeVecs["podPhase"].With(prometheus.Labels{
+			"cluster_name":  clusterName,
+			"namespace":     namespaceName,
+			"workload_name": workloadName,
+			"pod_name":      name,
+			"phase":         y,
+		})
+		if phase == y {
+			gauge.Set(1)
+		} else {
+			gauge.Set(0)
+		}
+	}
+
+	e.gaugeVecs["podRestarts"].With(prometheus.Labels{
+		"cluster_name":  clusterName,
+		"namespace":     namespaceName,
+		"workload_name": workloadName,
+		"pod_name":      name,
+	}).Set(float64(restarts))
+}
+
+// setAppMetrics - Logic to set the state, deployed version and upgrade availability of a catalog app as gauge metrics
+func (e *Exporter) setAppMetrics(name string, namespaceName string, projectName string, clusterName string, state string, catalog string, template string, version string, latest string) {
+	labels := prometheus.Labels{
+		"cluster_name": clusterName,
+		"project_name": projectName,
+		"namespace":    namespaceName,
+		"app_name":     name,
+	}
+
+	for _, y := range appStates {
+		stateLabels := prometheus.Labels{"state": y}
+		for k, v := range labels {
+			stateLabels[k] = v
+		}
+		gauge := e.gaugeVecs["appState"].With(stateLabels)
+		if state == y {
+			gauge.Set(1)
+		} else {
+			gauge.Set(0)
+		}
+	}
+
+	infoLabels := prometheus.Labels{
+		"catalog":  catalog,
+		"template": template,
+		"version":  version,
+	}
+	for k, v := range labels {
+		infoLabels[k] = v
+	}
+	e.gaugeVecs["appInfo"].With(infoLabels).Set(1)
+
+	// Without the catalog's versions there is nothing to compare against
+	if latest == "" || version == "" {
+		return
+	}
+	gauge := e.gaugeVecs["appUpgradeAvailable"].With(labels)
+	if compareVersions(latest, version) > 0 {
+		gauge.Set(1)
+	} else {
+		gauge.Set(0)
+	}
+}
+
+// setMultiClusterAppMetrics - Logic to set the state, deployed version and upgrade availability of a multi-cluster app as gauge metrics
+func (e *Exporter) setMultiClusterAppMetrics(name string, state string, catalog string, template string, version string, latest string) {
+	for _, y := range appStates {
+		gauge := e.gaugeVecs["multiClusterAppState"].With(prometheus.Labels{
+			"app_name": name,
+			"state":    y,
+		})
+		if state == y {
+			gauge.Set(1)
+		} else {
+			gauge.Set(0)
+		}
+	}
+
+	e.gaugeVecs["multiClusterAppInfo"].With(prometheus.Labels{
+		"app_name": name,
+		"catalog":  catalog,
+		"template": template,
+		"version":  version,
+	}).Set(1)
+
+	if latest == "" || version == "" {
+		return
+	}
+	gauge := e.gaugeVecs["multiClusterAppUpgradeAvailable"].With(prometheus.Labels{
+		"app_name": name,
+	})
+	if compareVersions(latest, version) > 0 {
+		gauge.Set(1)
+	} else {
+		gauge.Set(0)
+	}
+}
+
+// setTokenMetrics - Logic to set the token counts and the expiry of the exporter's own token as gauge metrics
+func (e *Exporter) setTokenMetrics(summary *securitySummary) {
+	e.gaugeVecs["tokens"].With(prometheus.Labels{}).Set(float64(summary.tokens))
+	e.gaugeVecs["tokensExpired"].With(prometheus.Labels{}).Set(float64(summary.expiredTokens))
+	e.gaugeVecs["tokensNonExpiring"].With(prometheus.Labels{}).Set(float64(summary.nonExpiringTokens))
+
+	if summary.ownTokenExpires {
+		e.gaugeVecs["ownTokenExpiry"].With(prometheus.Labels{}).Set(summary.ownTokenExpiry)
+	}
+}
+
+// setUserMetrics - Logic to set the user counts as gauge metrics
+func (e *Exporter) setUserMetrics(summary *securitySummary) {
+	e.gaugeVecs["users"].With(prometheus.Labels{}).Set(float64(summary.users))
+	e.gaugeVecs["usersEnabled"].With(prometheus.Labels{}).Set(float64(summary.enabledUsers))
+}
+
+// setGlobalAdminMetrics - Logic to set the number of global admins as a gauge metric
+func (e *Exporter) setGlobalAdminMetrics(summary *securitySummary) {
+	e.gaugeVecs["usersGlobalAdmins"].With(prometheus.Labels{}).Set(float64(len(summary.globalAdmins)))
+}
+
+// setNodePoolMetrics - Logic to set the desired size of a node pool as a gauge metric
+func (e *Exporter) setNodePoolMetrics(name string, clusterName string, templateName string, driver string, quantity int) {
+	e.gaugeVecs["nodePoolDesiredNodes"].With(prometheus.Labels{
+		"cluster_name":  clusterName,
+		"nodepool":      name,
+		"node_template": templateName,
+		"driver":        driver,
+	}).Set(float64(quantity))
+
+	// Nodes are counted as they are processed, start from zero so missing nodes are reported
+	for _, y := range nodeStates {
+		e.gaugeVecs["nodePoolNodes"].With(prometheus.Labels{
+			"cluster_name": clusterName,
+			"nodepool":     name,
+			"state":        y,
+		}).Set(0)
+	}
+}
+
+// countNodePoolNode - Adds a node to the count of nodes in its node pool
+func (e *Exporter) countNodePoolNode(pool string, clusterName string, state string) {
+	e.gaugeVecs["nodePoolNodes"].With(prometheus.Labels{
+		"cluster_name": clusterName,
+		"nodepool":     pool,
+		"state":        state,
+	}).Inc()
+}
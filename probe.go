@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// clusterProbe holds the settings used to check each downstream cluster's API through the Rancher proxy
+type clusterProbe struct {
+	enabled     bool
+	concurrency int
+	timeout     time.Duration
+}
+
+// clusterProbeResult is the outcome of a single cluster API probe, the version is empty if the probe failed
+type clusterProbeResult struct {
+	up       bool
+	duration float64
+	version  string
+}
+
+// probeClusters - Calls the version endpoint of every cluster through the Rancher proxy, at most concurrency at a time
+func (e *Exporter) probeClusters(clusters map[string]string) map[string]clusterProbeResult {
+	results := make(map[string]clusterProbeResult)
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, e.clusterProbe.concurrency)
+
+	for clusterID, clusterName := range clusters {
+		wg.Add(1)
+		go func(clusterID, clusterName string) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			result := e.probeCluster(clusterID)
+			<-sem
+
+			mutex.Lock()
+			results[clusterName] = result
+			mutex.Unlock()
+		}(clusterID, clusterName)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// probeCluster - Calls the version endpoint of a single cluster, a tunnel that is down fails or times out
+func (e *Exporter) probeCluster(clusterID string) (result clusterProbeResult) {
+	start := time.Now()
+	defer func() {
+		result.duration = time.Since(start).Seconds()
+	}()
+
+	url := serverRoot(e.rancherURL) + "/k8s/clusters/" + clusterID + "/version"
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		log.Error("Error probing cluster API: ", err)
+		return result
+	}
+	req.SetBasicAuth(e.accessKey, e.secretKey)
+
+	client := &http.Client{Timeout: e.clusterProbe.timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Warnf("Error probing cluster API of %s: %s", clusterID, err)
+		return result
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Warnf("Error probing cluster API of %s: %s", clusterID, resp.Status)
+		return result
+	}
+
+	var version KubernetesVersion
+	if err := json.NewDecoder(resp.Body).Decode(&version); err != nil {
+		log.Warnf("Error decoding cluster API version of %s: %s", clusterID, err)
+		return result
+	}
+
+	result.up = true
+	result.version = version.GitVersion
+	return result
+}
@@ -1,8 +1,6 @@
 package main
 
 import (
-	"strings"
-
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -19,6 +17,9 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	for _, m := range e.gaugeVecs {
 		m.Describe(ch)
 	}
+	for _, m := range e.customMetrics {
+		ch <- m.desc
+	}
 }
 
 // Collect function, called on by Prometheus Client library
@@ -28,30 +29,44 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 
 	e.resetGaugeVecs() // Clean starting point
 
-	var endpointOfAPI []string
-	if strings.HasSuffix(rancherURL, "v3") || strings.HasSuffix(rancherURL, "v3/") {
-		endpointOfAPI = endpointsV3
-	} else {
-		endpointOfAPI = endpoints
+	// The API is probed again after the server was unreachable, it may have been upgraded since it was detected
+	if e.apiVersion == "" {
+		e.detectAPI()
 	}
-	// Range over the pre-configured endpoints array
-	for _, p := range endpointOfAPI {
+	e.setAPIMetrics(e.apiVersion)
+	e.setCollectorMetrics(e.collectors)
+
+	// Probe the server itself first, so its health is reported even when the endpoints can't be scraped
+	healthy, duration, version := e.gatherServerStatus(e.apiVersion == "v3")
+	e.setServerMetrics(healthy, duration, version)
+
+	// Range over the endpoints served by the detected API
+	for _, p := range e.endpoints {
 
 		var data, err = e.gatherData(e.rancherURL, e.resourceLimit, e.accessKey, e.secretKey, p, ch)
 
+		// A failing endpoint doesn't stop the others
 		if err != nil {
 			log.Error("Error getting JSON from URL ", p)
-			return
+			continue
 		}
 
 		if err := e.processMetrics(data, p, e.hideSys, ch); err != nil {
 			log.Errorf("Error scraping rancher url: %s", err)
-			return
+			continue
 		}
 		log.Infof("Metrics successfully processed for %s", p)
 
 	}
 
+	// User defined metrics are sent as they are read, alongside the built-in collectors
+	e.collectCustomMetrics(ch)
+
+	// The API is probed again on the next scrape once the server answers, in case it has been upgraded while down
+	if !healthy {
+		e.apiVersion = ""
+	}
+
 	for _, m := range e.gaugeVecs {
 		m.Collect(ch)
 	}
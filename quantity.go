@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Multipliers and powers of ten for the suffixes Kubernetes accepts on resource quantities
+var (
+	binarySuffixes = map[string]float64{
+		"Ki": 1 << 10,
+		"Mi": 1 << 20,
+		"Gi": 1 << 30,
+		"Ti": 1 << 40,
+		"Pi": 1 << 50,
+		"Ei": 1 << 60,
+	}
+	// Decimal suffixes are powers of ten, fractions are divided out so that e.g. `10u` is exactly 1e-5
+	decimalSuffixes = map[string]int{
+		"n": -9,
+		"u": -6,
+		"m": -3,
+		"k": 3,
+		"M": 6,
+		"G": 9,
+		"T": 12,
+		"P": 15,
+		"E": 18,
+	}
+)
+
+// parseQuantity - Converts a Kubernetes resource quantity such as `500m`, `16Gi` or `1e3` into its value in base units
+func parseQuantity(quantity string) (float64, error) {
+	q := strings.TrimSpace(quantity)
+	if q == "" {
+		return 0, fmt.Errorf("empty quantity")
+	}
+
+	// Plain numbers, including the exponent form
+	if value, err := strconv.ParseFloat(q, 64); err == nil {
+		return finiteQuantity(quantity, value)
+	}
+
+	if len(q) > 2 {
+		if multiplier, ok := binarySuffixes[q[len(q)-2:]]; ok {
+			value, err := strconv.ParseFloat(q[:len(q)-2], 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid quantity %q: %v", quantity, err)
+			}
+			return finiteQuantity(quantity, value*multiplier)
+		}
+	}
+
+	if exponent, ok := decimalSuffixes[q[len(q)-1:]]; ok {
+		value, err := strconv.ParseFloat(q[:len(q)-1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid quantity %q: %v", quantity, err)
+		}
+		if exponent < 0 {
+			return finiteQuantity(quantity, value/math.Pow10(-exponent))
+		}
+		return finiteQuantity(quantity, value*math.Pow10(exponent))
+	}
+
+	return 0, fmt.Errorf("invalid quantity %q: unknown suffix", quantity)
+}
+
+// finiteQuantity - Rejects the NaN and infinite values strconv accepts, Kubernetes never reports them
+func finiteQuantity(quantity string, value float64) (float64, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("invalid quantity %q: not a finite number", quantity)
+	}
+	return value, nil
+}
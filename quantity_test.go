@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		quantity string
+		want     float64
+		wantErr  bool
+	}{
+		{quantity: "4", want: 4},
+		{quantity: "0.5", want: 0.5},
+		{quantity: " 2 ", want: 2},
+		{quantity: "500m", want: 0.5},
+		{quantity: "250n", want: 250e-9},
+		{quantity: "10u", want: 10e-6},
+		{quantity: "1k", want: 1e3},
+		{quantity: "2M", want: 2e6},
+		{quantity: "3G", want: 3e9},
+		{quantity: "1T", want: 1e12},
+		{quantity: "1P", want: 1e15},
+		{quantity: "16Ki", want: 16 * 1024},
+		{quantity: "1.5Mi", want: 1.5 * 1024 * 1024},
+		{quantity: "16Gi", want: 16 * 1024 * 1024 * 1024},
+		{quantity: "1Ti", want: 1 << 40},
+		{quantity: "1Pi", want: 1 << 50},
+		{quantity: "1Ei", want: 1 << 60},
+		// An upper case E on its own is the exa suffix, followed by digits it is an exponent
+		{quantity: "2E", want: 2e18},
+		{quantity: "1E3", want: 1e3},
+		{quantity: "1e3", want: 1e3},
+		{quantity: "1.5e-3", want: 1.5e-3},
+		{quantity: "", wantErr: true},
+		{quantity: "Ki", wantErr: true},
+		{quantity: "m", wantErr: true},
+		{quantity: "E", wantErr: true},
+		{quantity: "1Qi", wantErr: true},
+		{quantity: "1x", wantErr: true},
+		{quantity: "1e", wantErr: true},
+		{quantity: "NaN", wantErr: true},
+		{quantity: "Inf", wantErr: true},
+		{quantity: "-Inf", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseQuantity(tt.quantity)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseQuantity(%q) = %v, want an error", tt.quantity, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseQuantity(%q) returned error %v, want %v", tt.quantity, err, tt.want)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseQuantity(%q) = %v, want %v", tt.quantity, got, tt.want)
+		}
+	}
+}
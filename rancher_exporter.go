@@ -6,6 +6,7 @@ import (
 	"os"
 	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/infinityworks/prometheus-rancher-exporter/measure"
 	"github.com/prometheus/client_golang/prometheus"
@@ -16,6 +17,21 @@ import (
 const (
 	namespace           = "rancher" // Used to prepand Prometheus metrics created by this exporter.
 	defaultLabelsFilter = "^io.prometheus"
+	defaultFSTypeFilter = "^(tmpfs|overlay|shm)$"
+	memoryUnit          = 1024 * 1024                   // The agent reports memory in MB read from /proc/meminfo (kB / 1024)
+	diskUnit            = 1000 * 1000                   // The agent reports mount point sizes in MB of 10^6 bytes
+	agentImageLabel     = "io.rancher.host.agent_image" // Host label holding the Rancher agent image, reported as the agent version
+
+	serverVersionSetting   = "rancher.server.version" // Setting holding the server version in the v1 / v2-beta API
+	serverVersionSettingV3 = "server-version"         // Setting holding the server version in the v3 API
+	pingTimeout            = 10 * time.Second         // Time allowed for the Rancher server to answer a ping
+	templatesLimit         = "-1"                     // Catalog templates are listed in full, there are usually more than API_LIMIT of them
+	countedLimit           = "-1"                     // Collections whose items are counted are listed in full, their counts would otherwise stop at API_LIMIT
+	apiSteve               = "steve"                  // Version reported for the Rancher 2.5+ Steve API, which shares the v1 path with Rancher 1.x
+
+	systemProject         = "System"  // Project holding Rancher's own workloads, hidden by HIDE_SYS
+	systemNamespacePrefix = "cattle-" // Prefix of Rancher's own namespaces, hidden by HIDE_SYS
+	globalAdminRole       = "admin"   // Global role granting full control of Rancher
 )
 
 // Runtime variables, user controllable for targeting, authentication and filtering.
@@ -31,6 +47,32 @@ var (
 	logLevel      = getEnv("LOG_LEVEL", "info")                   // Optional - Set the logging level
 	resourceLimit = getEnv("API_LIMIT", "100")                    // Optional - Rancher API resource limit (default: 100)
 	hideSys, _    = strconv.ParseBool(getEnv("HIDE_SYS", "true")) // hideSys - Optional - Flag that indicates if the environment variable `HIDE_SYS` is set to a boolean true value
+
+	mountPointInclude = os.Getenv("MOUNTPOINT_INCLUDE")                          // Optional - Only export host mount points matching this regular expression
+	mountPointExclude = os.Getenv("MOUNTPOINT_EXCLUDE")                          // Optional - Skip host mount points matching this regular expression
+	fsTypeInclude     = os.Getenv("FSTYPE_INCLUDE")                              // Optional - Only export host mount points whose filesystem type matches this regular expression
+	fsTypeExclude     = getEnv("FSTYPE_EXCLUDE", defaultFSTypeFilter)            // Optional - Skip host mount points whose filesystem type matches this regular expression
+	legacyHostMB, _   = strconv.ParseBool(getEnv("LEGACY_HOST_METRICS", "true")) // Optional - Keep publishing the host memory and mount point metrics in MB under their original names
+	namespaceInclude  = os.Getenv("NAMESPACE_INCLUDE")                           // Optional - Only export workloads in namespaces matching this regular expression
+	namespaceExclude  = os.Getenv("NAMESPACE_EXCLUDE")                           // Optional - Skip workloads in namespaces matching this regular expression
+
+	collectPods, _ = strconv.ParseBool(getEnv("COLLECT_PODS", "false"))        // Optional - Collect pod counts from the Rancher 2.x API
+	podsPerPod, _  = strconv.ParseBool(getEnv("POD_METRICS_PER_POD", "false")) // Optional - Export a series per pod rather than only per workload, only suited to small clusters
+
+	catalogCacheTTL = getEnv("CATALOG_CACHE_TTL", "1h") // Optional - How long catalog templates are cached before checking for new versions again
+
+	collectSecurity, _ = strconv.ParseBool(getEnv("COLLECT_SECURITY", "false")) // Optional - Collect token and user hygiene metrics from the Rancher 2.x API
+
+	probeClusterAPI, _  = strconv.ParseBool(getEnv("CLUSTER_PROBE", "false"))    // Optional - Call each cluster's Kubernetes API through the Rancher proxy to check the agent tunnel
+	probeConcurrency, _ = strconv.Atoi(getEnv("CLUSTER_PROBE_CONCURRENCY", "5")) // Optional - Number of clusters probed at the same time
+	probeTimeout        = getEnv("CLUSTER_PROBE_TIMEOUT", "5s")                  // Optional - Time allowed for each cluster to answer the probe
+
+	useSteveAPI, _  = strconv.ParseBool(getEnv("STEVE_API", "false"))     // Optional - Collect clusters, nodes and projects from the Rancher 2.5+ Steve (/v1) API rather than the v3 API
+	collectFleet, _ = strconv.ParseBool(getEnv("COLLECT_FLEET", "false")) // Optional - Collect Fleet GitRepo and bundle readiness from the Steve API
+
+	collectBackups, _ = strconv.ParseBool(getEnv("COLLECT_BACKUPS", "false")) // Optional - Collect rancher-backup operator Backup and Restore status from the Steve API
+
+	customMetricsConfig = os.Getenv("CUSTOM_METRICS_CONFIG") // Optional - Path of a JSON file declaring metrics read from arbitrary Rancher API collections
 )
 
 // Predefined variables that are used throughout the exporter
@@ -43,12 +85,95 @@ var (
 	healthStates    = []string{"healthy", "unhealthy", "initializing", "degraded", "started-once"}
 	componentStatus = []string{"True", "False", "Unknown"}
 	nodeStates      = []string{"active", "cordoned", "drained", "draining", "provisioning", "registering", "unavailable"}
-	endpoints       = []string{"stacks", "services", "hosts"} // EndPoints the exporter will trawl
-	endpointsV3     = []string{"clusters", "nodes"}           // EndPoints the exporter will trawl]
+	nodeResources   = []string{"cpu", "memory", "pods"}
+	nodeRoles       = []string{"etcd", "controlplane", "worker"}
+	projectStates   = []string{"active", "initializing", "removing", "unavailable", "updating"}
+	podPhases       = []string{"Pending", "Running", "Succeeded", "Failed", "Unknown"}
+	appStates       = []string{"active", "deploying", "error", "installing", "pending", "removing", "upgrading"}
+	workloadStates  = []string{"active", "error", "initializing", "paused", "pending", "removing", "scaling", "unavailable", "updating"}
+	endpoints       = []string{ // EndPoints the exporter will trawl
+		"stacks",
+		"services",
+		"hosts",
+		"certificates",
+	}
+	endpointsV3 = []string{ // EndPoints the exporter will trawl]
+		"clusters",
+		"nodetemplates",
+		"nodepools",
+		"nodes",
+		"projects",
+		"namespaces",
+		"workloads",
+		"apps",
+		"multiclusterapps",
+		"etcdbackups",
+		"clusterscans",
+	}
+	endpointsSteve = []string{ // EndPoints the exporter will trawl on the Steve API
+		"clusters",
+		"nodes",
+		"projects",
+	}
+	steveKinds = map[string]string{ // Steve resource types holding each endpoint
+		"clusters": "management.cattle.io.clusters",
+		"nodes":    "management.cattle.io.nodes",
+		"projects": "management.cattle.io.projects",
+		"gitrepos": "fleet.cattle.io.gitrepos",
+		"bundles":  "fleet.cattle.io.bundles",
+		"backups":  "resources.cattle.io.backups",
+		"restores": "resources.cattle.io.restores",
+	}
+	steveMoved = map[string]bool{ // EndPoints read from the Steve API instead of the v3 API when STEVE_API is set
+		"clusters": true,
+		"nodes":    true,
+		"projects": true,
+	}
+	steveOnly = map[string]bool{ // EndPoints only found on the Steve API, fetched from it whichever API is in use
+		"gitrepos": true,
+		"bundles":  true,
+		"backups":  true,
+		"restores": true,
+	}
+	countedEndpoints = map[string]bool{ // EndPoints whose items are counted, listed in full rather than cut off at API_LIMIT
+		"namespaces":         true,
+		"pods":               true,
+		"etcdbackups":        true,
+		"tokens":             true,
+		"users":              true,
+		"globalrolebindings": true,
+	}
+	clusterScoped = map[string]bool{ // EndPoints that are nested under each cluster
+		"namespaces": true,
+	}
+	projectScoped = map[string]bool{ // EndPoints that are nested under each project
+		"workloads": true,
+		"pods":      true,
+		"apps":      true,
+	}
+	apiVersionPath  = regexp.MustCompile(`^v[0-9]+(-beta)?$`) // Matches the API version at the end of the Rancher URL
 	stackRef        = make(map[string]string)                 // Stores the StackID and StackName as a map, used to provide label dimensions to service metrics
 	clusterRef      = make(map[string]string)                 // Stores the ClusterID and ClusterName as a map, used to provide label dimensions to node metrics
+	nodePoolRef     = make(map[string]string)                 // Stores the NodePoolID and hostname prefix as a map, used to count nodes per node pool
+	nodeTemplates   = make(map[string]nodeTemplate)           // Stores the NodeTemplateID and template details as a map, used to provide label dimensions to node pool metrics
+	projectRef      = make(map[string]string)                 // Stores the ProjectID and ProjectName as a map, used to provide label dimensions to namespace metrics
+	clusterVersions = make(map[string]string)                 // Stores the ClusterID and Kubernetes version as a map, used to compare node versions against their cluster
+	clusterLinks    = make(map[string]map[string]string)      // Stores the ClusterID and the links of the cluster as a map, used to find the collections nested under it
+	projectLinks    = make(map[string]map[string]string)      // Stores the ProjectID and the links of the project as a map, used to find the collections nested under it
 )
 
+// compileFilter - Compiles an optional regular expression taken from the environment, an empty value disables the filter
+func compileFilter(key, expr string) *regexp.Regexp {
+	if expr == "" {
+		return nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		log.Fatalf("%s must be valid regular expression", key)
+	}
+	return re
+}
+
 // getEnv - Allows us to supply a fallback option if nothing specified
 func getEnv(key, fallback string) string {
 	value := os.Getenv(key)
@@ -78,6 +203,62 @@ func main() {
 		log.Fatal("LABELS_FILTER must be valid regular expression")
 	}
 
+	hostMountFilter := mountFilter{
+		mountPointInclude: compileFilter("MOUNTPOINT_INCLUDE", mountPointInclude),
+		mountPointExclude: compileFilter("MOUNTPOINT_EXCLUDE", mountPointExclude),
+		fsTypeInclude:     compileFilter("FSTYPE_INCLUDE", fsTypeInclude),
+		fsTypeExclude:     compileFilter("FSTYPE_EXCLUDE", fsTypeExclude),
+	}
+
+	workloadNamespaceFilter := namespaceFilter{
+		include: compileFilter("NAMESPACE_INCLUDE", namespaceInclude),
+		exclude: compileFilter("NAMESPACE_EXCLUDE", namespaceExclude),
+	}
+
+	catalogTTL, err := time.ParseDuration(catalogCacheTTL)
+	if err != nil {
+		log.Fatal("CATALOG_CACHE_TTL must be a valid duration e.g. 1h")
+	}
+
+	clusterAPIProbe := clusterProbe{enabled: probeClusterAPI, concurrency: probeConcurrency}
+	clusterAPIProbe.timeout, err = time.ParseDuration(probeTimeout)
+	if err != nil {
+		log.Fatal("CLUSTER_PROBE_TIMEOUT must be a valid duration e.g. 5s")
+	}
+	if clusterAPIProbe.concurrency < 1 {
+		log.Fatal("CLUSTER_PROBE_CONCURRENCY must be a positive number")
+	}
+
+	var customMetrics []*customMetric
+	if customMetricsConfig != "" {
+		customMetrics, err = loadCustomMetrics(customMetricsConfig)
+		if err != nil {
+			log.Fatal("CUSTOM_METRICS_CONFIG must be a valid custom metrics config: ", err)
+		}
+	}
+
+	// Pods are opt-in, the number of pods makes them expensive to collect on large installations
+	if collectPods {
+		endpointsV3 = append(endpointsV3, "pods")
+	}
+
+	// Security metrics are opt-in, they need a key allowed to list tokens, users and role bindings
+	if collectSecurity {
+		endpointsV3 = append(endpointsV3, "tokens", "users", "globalrolebindings")
+	}
+
+	// Fleet is opt-in, it is only installed with Rancher 2.5+
+	if collectFleet {
+		endpointsV3 = append(endpointsV3, "gitrepos", "bundles")
+		endpointsSteve = append(endpointsSteve, "gitrepos", "bundles")
+	}
+
+	// Rancher's own backups are opt-in, the rancher-backup operator has to be installed in the local cluster
+	if collectBackups {
+		endpointsV3 = append(endpointsV3, "backups", "restores")
+		endpointsSteve = append(endpointsSteve, "backups", "restores")
+	}
+
 	log.Info("Starting Prometheus Exporter for Rancher")
 	log.Info(
 		"Runtime Configuration in-use: URL of Rancher Server: ",
@@ -88,13 +269,18 @@ func main() {
 		hideSys,
 		" Labels filter: ",
 		labelsFilter,
+		" Steve API: ",
+		useSteveAPI,
 	)
 
 	// Register internal metrics used for tracking the exporter performance
 	measure.Init()
 
 	// Register a new Exporter
-	exporter := newExporter(rancherURL, accessKey, secretKey, labelsFilterRegexp, hideSys, resourceLimit)
+	exporter := newExporter(rancherURL, accessKey, secretKey, labelsFilterRegexp, hideSys, resourceLimit, hostMountFilter, legacyHostMB, workloadNamespaceFilter, podsPerPod, catalogTTL, clusterAPIProbe, useSteveAPI, customMetrics)
+
+	// Detect the API up front, if Rancher can't be reached yet it is retried on the first scrape
+	exporter.detectAPI()
 
 	// Register Metrics from each of the endpoints
 	// This invokes the Collect method through the prometheus client libraries.
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// SteveData is used to store data from a Steve (/v1) API collection
+type SteveData struct {
+	Data []SteveResource `json:"data"`
+}
+
+// SteveResource is a Kubernetes object as returned by the Steve API, the spec and status are kept raw and decoded per kind
+type SteveResource struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Metadata struct {
+		Name              string            `json:"name"`
+		Namespace         string            `json:"namespace"`
+		Labels            map[string]string `json:"labels"`
+		CreationTimestamp string            `json:"creationTimestamp"`
+		// State is summarised by Steve from the object's conditions
+		State struct {
+			Name          string `json:"name"`
+			Error         bool   `json:"error"`
+			Transitioning bool   `json:"transitioning"`
+		} `json:"state"`
+	} `json:"metadata"`
+	Spec   json.RawMessage `json:"spec"`
+	Status json.RawMessage `json:"status"`
+}
+
+// steveNodeDetails holds the node fields that the v3 API lifts out of the embedded Kubernetes node
+type steveNodeDetails struct {
+	// NodePoolName is the v3 ID of the node pool, `<clusterID>:<poolID>`
+	NodePoolName     string `json:"nodePoolName"`
+	InternalNodeSpec struct {
+		Unschedulable bool     `json:"unschedulable"`
+		Taints        []*Taint `json:"taints"`
+	} `json:"internalNodeSpec"`
+	InternalNodeStatus struct {
+		Capacity    map[string]string `json:"capacity"`
+		Allocatable map[string]string `json:"allocatable"`
+		NodeInfo    struct {
+			KubeletVersion          string `json:"kubeletVersion"`
+			KernelVersion           string `json:"kernelVersion"`
+			OSImage                 string `json:"osImage"`
+			ContainerRuntimeVersion string `json:"containerRuntimeVersion"`
+		} `json:"nodeInfo"`
+	} `json:"internalNodeStatus"`
+}
+
+// steveBackupDetails holds the rancher-backup Backup and Restore fields the exporter reads
+type steveBackupDetails struct {
+	RetentionCount      int    `json:"retentionCount"`
+	LastSnapshotTS      string `json:"lastSnapshotTs"`
+	RestoreCompletionTS string `json:"restoreCompletionTs"`
+}
+
+// gatherSteveData - Collects an endpoint from the Steve API and converts it into the shape of the v3 API
+func (e *Exporter) gatherSteveData(rancherURL string, resourceLimit string, accessKey string, secretKey string, endpoint string) (*Data, error) {
+	url := e.collectionURL(endpoint, resourceLimit)
+
+	var steve = new(SteveData)
+	if err := getJSON(url, accessKey, secretKey, &steve); err != nil {
+		log.Error("Error getting JSON from endpoint ", endpoint)
+		return nil, err
+	}
+
+	var data = new(Data)
+	for _, r := range steve.Data {
+		resource, err := r.resource(endpoint)
+		if err != nil {
+			log.Warnf("Error decoding %s %s from the Steve API: %s", endpoint, r.ID, err)
+			continue
+		}
+		data.Data = append(data.Data, resource)
+	}
+	log.Debugf("JSON Fetched for: "+endpoint+": %+v", data)
+
+	return data, nil
+}
+
+// resource - Flattens a Steve object the way the v3 API does, embedding its spec and status and deriving the v3 IDs
+func (r *SteveResource) resource(endpoint string) (Resource, error) {
+	x := Resource{
+		ID:            r.Metadata.Name,
+		Type:          strings.TrimSuffix(endpoint, "s"),
+		State:         r.Metadata.State.Name,
+		Labels:        r.Metadata.Labels,
+		Transitioning: "no",
+	}
+	if r.Metadata.State.Error {
+		x.Transitioning = "error"
+	} else if r.Metadata.State.Transitioning {
+		x.Transitioning = "yes"
+	}
+
+	// rancher-backup objects share field names such as `summary` with other kinds but not their types, they are decoded on their own
+	if endpoint == "backups" || endpoint == "restores" {
+		return r.backupResource(x)
+	}
+
+	var names struct {
+		DisplayName string `json:"displayName"`
+	}
+	for _, raw := range []json.RawMessage{r.Spec, r.Status} {
+		if len(raw) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(raw, &x); err != nil {
+			return x, err
+		}
+	}
+	if len(r.Spec) > 0 {
+		if err := json.Unmarshal(r.Spec, &names); err != nil {
+			return x, err
+		}
+	}
+	x.Name = names.DisplayName
+	if x.Name == "" {
+		x.Name = r.Metadata.Name
+	}
+
+	// Nodes and projects live in the namespace of their cluster, the v3 API prefixes their IDs with it
+	if strings.HasPrefix(steveKinds[endpoint], "management.cattle.io.") && r.Metadata.Namespace != "" {
+		x.ClusterID = r.Metadata.Namespace
+		x.ID = r.Metadata.Namespace + ":" + r.Metadata.Name
+	} else {
+		x.NamespaceID = r.Metadata.Namespace
+	}
+
+	// The Kubernetes version is only decoded for clusters, other kinds use `version` differently
+	if endpoint == "clusters" && len(r.Status) > 0 {
+		var cluster clusterDetails
+		if err := json.Unmarshal(r.Status, &cluster); err != nil {
+			return x, err
+		}
+		x.Version = cluster.Version
+	}
+
+	if endpoint == "nodes" && len(r.Spec) > 0 && len(r.Status) > 0 {
+		var node steveNodeDetails
+		if err := json.Unmarshal(r.Spec, &node); err != nil {
+			return x, err
+		}
+		if err := json.Unmarshal(r.Status, &node); err != nil {
+			return x, err
+		}
+
+		x.NodePoolID = node.NodePoolName
+		x.Unschedulable = node.InternalNodeSpec.Unschedulable
+		x.Taints = node.InternalNodeSpec.Taints
+		x.Capacity = node.InternalNodeStatus.Capacity
+		x.Allocatable = node.InternalNodeStatus.Allocatable
+
+		info := node.InternalNodeStatus.NodeInfo
+		x.HostInfo = new(HostInfo)
+		x.HostInfo.OS.DockerVersion = info.ContainerRuntimeVersion
+		x.HostInfo.OS.KernelVersion = info.KernelVersion
+		x.HostInfo.OS.OperatingSystem = info.OSImage
+		x.HostInfo.Kubernetes.KubeletVersion = info.KubeletVersion
+	}
+
+	return x, nil
+}
+
+// backupResource - Reads the snapshot times and retention of a rancher-backup Backup or Restore, both are cluster scoped
+func (r *SteveResource) backupResource(x Resource) (Resource, error) {
+	var backup steveBackupDetails
+	for _, raw := range []json.RawMessage{r.Spec, r.Status} {
+		if len(raw) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(raw, &backup); err != nil {
+			return x, err
+		}
+	}
+
+	x.Name = r.Metadata.Name
+	x.RetentionCount = backup.RetentionCount
+	x.LastSnapshotTS = backup.LastSnapshotTS
+	x.RestoreCompletionTS = backup.RestoreCompletionTS
+	return x, nil
+}